@@ -0,0 +1,324 @@
+/*
+ * Copyright 2020 Dennis Kuhnert
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+// Package balltree implements a ball tree for the points of the k-d tree.
+//
+// Every node of a ball tree bounds its points with a ball instead of a box. The balls adapt better to
+// clustered data in many dimensions, where the k-d tree degrades to a linear scan.
+//
+// The queries accept the kdtree.QueryOption to collect kdtree.QueryStats.
+package balltree
+
+import (
+	"github.com/kyroy/kdtree"
+	"github.com/kyroy/priority-queue"
+	"math"
+	"sort"
+)
+
+// BallTree represents the ball tree.
+type BallTree struct {
+	root     *node
+	leafSize int
+}
+
+// Option configures a BallTree.
+type Option func(*BallTree)
+
+// WithLeafSize sets the maximum number of points in a leaf. The default is 16.
+func WithLeafSize(n int) Option {
+	return func(t *BallTree) {
+		if n > 0 {
+			t.leafSize = n
+		}
+	}
+}
+
+// New returns a ball tree containing the given points.
+// The points of every node are split at the median of the dimension in which they are spread the most.
+func New(points []kdtree.Point, options ...Option) *BallTree {
+	t := &BallTree{leafSize: 16}
+	for _, option := range options {
+		option(t)
+	}
+	if len(points) > 0 {
+		t.root = newBallTree(append([]kdtree.Point(nil), points...), t.leafSize)
+	}
+	return t
+}
+
+func newBallTree(points []kdtree.Point, leafSize int) *node {
+	dims := points[0].Dimensions()
+	n := &node{center: make([]float64, dims), size: len(points)}
+	for _, p := range points {
+		for i := range n.center {
+			n.center[i] += p.Dimension(i)
+		}
+	}
+	for i := range n.center {
+		n.center[i] /= float64(len(points))
+	}
+	for _, p := range points {
+		n.radius = math.Max(n.radius, distance(n.center, p))
+	}
+
+	if len(points) <= leafSize {
+		n.points = points
+		return n
+	}
+
+	axis, largest := 0, -1.
+	for i := 0; i < dims; i++ {
+		low, high := math.Inf(1), math.Inf(-1)
+		for _, p := range points {
+			low, high = math.Min(low, p.Dimension(i)), math.Max(high, p.Dimension(i))
+		}
+		if high-low > largest {
+			axis, largest = i, high-low
+		}
+	}
+	sort.Slice(points, func(i, j int) bool {
+		return points[i].Dimension(axis) < points[j].Dimension(axis)
+	})
+	mid := len(points) / 2
+	n.left = newBallTree(points[:mid], leafSize)
+	n.right = newBallTree(points[mid:], leafSize)
+	return n
+}
+
+// Len returns the number of points in the tree.
+func (t *BallTree) Len() int {
+	if t.root == nil {
+		return 0
+	}
+	return t.root.size
+}
+
+// Points returns all points in the tree.
+func (t *BallTree) Points() []kdtree.Point {
+	points := []kdtree.Point{}
+	if t.root != nil {
+		t.root.visit(func(p kdtree.Point) {
+			points = append(points, p)
+		})
+	}
+	return points
+}
+
+// KNN returns the k-nearest neighbours of the given point.
+// The points are sorted by the distance to the given points. Starting with the nearest.
+//
+// Statistics about the search are collected with kdtree.WithQueryStats.
+//
+// Returns an empty slice when p is nil or p.Dimensions() does not equal the dimensions of the tree's points.
+func (t *BallTree) KNN(p kdtree.Point, k int, options ...kdtree.QueryOption) []kdtree.Point {
+	if !t.queryable(p) || k <= 0 {
+		return []kdtree.Point{}
+	}
+
+	s := &knnSearch{p: coordinates(p), k: k, nearest: pq.NewPriorityQueue(pq.WithMinPrioSize(k))}
+	s.stats.DistanceComputations++
+	s.search(t.root, distance(t.root.center, p), 1)
+	if stats := kdtree.RequestedQueryStats(options...); stats != nil {
+		stats.Add(s.stats)
+	}
+
+	points := make([]kdtree.Point, 0, k)
+	for s.nearest.Len() > 0 {
+		points = append(points, s.nearest.PopLowest().(kdtree.Point))
+	}
+	return points
+}
+
+// RadiusSearch returns all points whose distance to the given point p is at most radius.
+// The points are sorted by the distance to the given points. Starting with the nearest.
+//
+// Statistics about the search are collected with kdtree.WithQueryStats.
+//
+// Returns an empty slice when p is nil or p.Dimensions() does not equal the dimensions of the tree's points.
+func (t *BallTree) RadiusSearch(p kdtree.Point, radius float64, options ...kdtree.QueryOption) []kdtree.Point {
+	if !t.queryable(p) || radius < 0 {
+		return []kdtree.Point{}
+	}
+
+	s := &radiusSearch{p: coordinates(p), radius: radius, result: &byDistance{points: []kdtree.Point{}}}
+	s.stats.DistanceComputations++
+	s.search(t.root, distance(t.root.center, p), 1)
+	if stats := kdtree.RequestedQueryStats(options...); stats != nil {
+		stats.Add(s.stats)
+	}
+
+	sort.Stable(s.result)
+	return s.result.points
+}
+
+func (t *BallTree) queryable(p kdtree.Point) bool {
+	return t.root != nil && p != nil && p.Dimensions() == len(t.root.center)
+}
+
+// node is a ball with the given center and radius, which contains all points of the subtree.
+// Leaves contain the points, inner nodes two children.
+type node struct {
+	center []float64
+	radius float64
+	size   int
+	points []kdtree.Point
+	left   *node
+	right  *node
+}
+
+func (n *node) visit(fn func(kdtree.Point)) {
+	for _, p := range n.points {
+		fn(p)
+	}
+	if n.left != nil {
+		n.left.visit(fn)
+		n.right.visit(fn)
+	}
+}
+
+// knnSearch holds the state of a k-nearest neighbour search.
+type knnSearch struct {
+	p       []float64
+	k       int
+	nearest *pq.PriorityQueue
+	stats   kdtree.QueryStats
+}
+
+// kth returns the distance of the k-th nearest neighbour found so far.
+func (s *knnSearch) kth() float64 {
+	if s.nearest.Len() < s.k {
+		return math.Inf(1)
+	}
+	_, d := s.nearest.Get(s.k - 1)
+	return d
+}
+
+// search adds the nearest neighbours in the subtree n to the queue.
+// centerDistance is the distance of the query point to the center of n.
+func (s *knnSearch) search(n *node, centerDistance float64, depth int) {
+	// no point of the ball is nearer than its surface
+	if centerDistance-n.radius >= s.kth() {
+		return
+	}
+	s.stats.NodesVisited++
+	if depth > s.stats.MaxDepth {
+		s.stats.MaxDepth = depth
+	}
+
+	if n.left == nil {
+		for _, p := range n.points {
+			s.stats.DistanceComputations++
+			if d := distance(s.p, p); d < s.kth() {
+				s.stats.QueueInserts++
+				s.nearest.Insert(p, d)
+			}
+		}
+		return
+	}
+
+	// search the nearer child first
+	s.stats.DistanceComputations += 2
+	first, second := n.left, n.right
+	firstDistance, secondDistance := distance(first.center, pointOf(s.p)), distance(second.center, pointOf(s.p))
+	if secondDistance < firstDistance {
+		first, second = second, first
+		firstDistance, secondDistance = secondDistance, firstDistance
+	}
+	s.search(first, firstDistance, depth+1)
+	s.search(second, secondDistance, depth+1)
+}
+
+// radiusSearch holds the state of a radius search.
+type radiusSearch struct {
+	p      []float64
+	radius float64
+	result *byDistance
+	stats  kdtree.QueryStats
+}
+
+func (s *radiusSearch) search(n *node, centerDistance float64, depth int) {
+	if centerDistance-n.radius > s.radius {
+		return
+	}
+	s.stats.NodesVisited++
+	if depth > s.stats.MaxDepth {
+		s.stats.MaxDepth = depth
+	}
+
+	if n.left == nil {
+		for _, p := range n.points {
+			s.stats.DistanceComputations++
+			if d := distance(s.p, p); d <= s.radius {
+				s.result.points = append(s.result.points, p)
+				s.result.distances = append(s.result.distances, d)
+			}
+		}
+		return
+	}
+
+	s.stats.DistanceComputations += 2
+	s.search(n.left, distance(n.left.center, pointOf(s.p)), depth+1)
+	s.search(n.right, distance(n.right.center, pointOf(s.p)), depth+1)
+}
+
+// pointOf wraps coordinates as a kdtree.Point.
+type pointOf []float64
+
+func (p pointOf) Dimensions() int {
+	return len(p)
+}
+
+func (p pointOf) Dimension(i int) float64 {
+	return p[i]
+}
+
+func coordinates(p kdtree.Point) []float64 {
+	c := make([]float64, p.Dimensions())
+	for i := range c {
+		c[i] = p.Dimension(i)
+	}
+	return c
+}
+
+// distance returns the euclidean distance between the coordinates c and the point p.
+func distance(c []float64, p kdtree.Point) float64 {
+	sum := 0.
+	for i, v := range c {
+		d := v - p.Dimension(i)
+		sum += d * d
+	}
+	return math.Sqrt(sum)
+}
+
+// byDistance sorts points by their distances.
+type byDistance struct {
+	points    []kdtree.Point
+	distances []float64
+}
+
+func (b *byDistance) Len() int {
+	return len(b.points)
+}
+
+func (b *byDistance) Less(i, j int) bool {
+	return b.distances[i] < b.distances[j]
+}
+
+func (b *byDistance) Swap(i, j int) {
+	b.points[i], b.points[j] = b.points[j], b.points[i]
+	b.distances[i], b.distances[j] = b.distances[j], b.distances[i]
+}
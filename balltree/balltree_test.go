@@ -0,0 +1,156 @@
+/*
+ * Copyright 2020 Dennis Kuhnert
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package balltree_test
+
+import (
+	"fmt"
+	"github.com/kyroy/kdtree"
+	"github.com/kyroy/kdtree/balltree"
+	"github.com/kyroy/kdtree/kdtreetest"
+	. "github.com/kyroy/kdtree/points"
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	assert.Equal(t, 0, balltree.New(nil).Len())
+	assert.Equal(t, []kdtree.Point{}, balltree.New(nil).Points())
+
+	input := generateClusters(rand.New(rand.NewSource(1)), 3, 100)
+	tree := balltree.New(input, balltree.WithLeafSize(4))
+	assert.Equal(t, 100, tree.Len())
+	assert.ElementsMatch(t, input, tree.Points())
+}
+
+func TestBallTree_KNN(t *testing.T) {
+	tree := balltree.New([]kdtree.Point{&Point2D{X: 1, Y: 1}, &Point2D{X: 3, Y: 1}, &Point2D{X: 8, Y: 3}, &Point2D{X: 6, Y: 6}}, balltree.WithLeafSize(1))
+
+	assert.Equal(t, []kdtree.Point{}, balltree.New(nil).KNN(&Point2D{}, 1))
+	assert.Equal(t, []kdtree.Point{}, tree.KNN(nil, 1))
+	assert.Equal(t, []kdtree.Point{}, tree.KNN(&Point3D{}, 1))
+	assert.Equal(t, []kdtree.Point{}, tree.KNN(&Point2D{}, 0))
+	assert.Equal(t, []kdtree.Point{&Point2D{X: 3, Y: 1}, &Point2D{X: 1, Y: 1}}, tree.KNN(&Point2D{X: 4, Y: 0}, 2))
+	assert.Equal(t, []kdtree.Point{&Point2D{X: 6, Y: 6}, &Point2D{X: 8, Y: 3}, &Point2D{X: 3, Y: 1}, &Point2D{X: 1, Y: 1}}, tree.KNN(&Point2D{X: 7, Y: 7}, 10))
+}
+
+func TestBallTree_RadiusSearch(t *testing.T) {
+	tree := balltree.New([]kdtree.Point{&Point2D{X: 1, Y: 1}, &Point2D{X: 3, Y: 1}, &Point2D{X: 8, Y: 3}, &Point2D{X: 6, Y: 6}}, balltree.WithLeafSize(1))
+
+	assert.Equal(t, []kdtree.Point{}, balltree.New(nil).RadiusSearch(&Point2D{}, 1))
+	assert.Equal(t, []kdtree.Point{}, tree.RadiusSearch(nil, 1))
+	assert.Equal(t, []kdtree.Point{}, tree.RadiusSearch(&Point2D{}, -1))
+	assert.Equal(t, []kdtree.Point{&Point2D{X: 3, Y: 1}}, tree.RadiusSearch(&Point2D{X: 3, Y: 1}, 0))
+	assert.Equal(t, []kdtree.Point{&Point2D{X: 3, Y: 1}, &Point2D{X: 1, Y: 1}}, tree.RadiusSearch(&Point2D{X: 3, Y: 2}, 2.5))
+}
+
+func TestBallTreeWithGenerator(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, dims := range []int{1, 2, 8, 32} {
+		for _, leafSize := range []int{1, 16} {
+			t.Run(fmt.Sprintf("dims:%d,leaf:%d", dims, leafSize), func(t *testing.T) {
+				input := generateClusters(r, dims, 500)
+				tree := balltree.New(input, balltree.WithLeafSize(leafSize))
+				for i := 0; i < 20; i++ {
+					p := generateClusters(r, dims, 1)[0]
+
+					var stats kdtree.QueryStats
+					kdtreetest.AssertSameDistances(t, p, kdtreetest.BruteForceKNN(input, p, 10), tree.KNN(p, 10, kdtree.WithQueryStats(&stats)))
+					assert.True(t, stats.NodesVisited > 0)
+					assert.True(t, stats.DistanceComputations > 0)
+
+					radius := kdtreetest.Distance(p, input[r.Intn(len(input))])
+					var expected []kdtree.Point
+					for _, q := range input {
+						if kdtreetest.Distance(p, q) <= radius {
+							expected = append(expected, q)
+						}
+					}
+					actual := tree.RadiusSearch(p, radius)
+					kdtreetest.AssertSamePoints(t, expected, actual)
+					for j := 1; j < len(actual); j++ {
+						assert.LessOrEqual(t, kdtreetest.Distance(p, actual[j-1]), kdtreetest.Distance(p, actual[j]))
+					}
+				}
+			})
+		}
+	}
+}
+
+// BenchmarkKNN compares the ball tree to the k-d tree and a linear scan by dimensionality.
+// The points lie in few clusters of low intrinsic dimension, as typical for high-dimensional data.
+func BenchmarkKNN(b *testing.B) {
+	for _, dims := range []int{2, 8, 16, 32, 64} {
+		r := rand.New(rand.NewSource(1))
+		input := generateClusters(r, dims, 10000)
+		queries := generateClusters(r, dims, 100)
+
+		indexes := []struct {
+			name string
+			knn  func(p kdtree.Point, k int, options ...kdtree.QueryOption) []kdtree.Point
+		}{
+			{name: "kdtree", knn: kdtree.New(append([]kdtree.Point(nil), input...)).KNN},
+			{name: "balltree", knn: balltree.New(input).KNN},
+			{name: "linear", knn: kdtree.NewLinearIndex(input).KNN},
+		}
+		for _, index := range indexes {
+			b.Run(fmt.Sprintf("dims:%d/%s", dims, index.name), func(b *testing.B) {
+				var stats kdtree.QueryStats
+				for i := 0; i < b.N; i++ {
+					index.knn(queries[i%len(queries)], 10, kdtree.WithQueryStats(&stats))
+				}
+				b.ReportMetric(float64(stats.DistanceComputations)/float64(b.N), "distances/op")
+			})
+		}
+	}
+}
+
+// generateClusters returns n points around 10 random centers. Each cluster spans a random 4-dimensional subspace.
+func generateClusters(r *rand.Rand, dims, n int) []kdtree.Point {
+	const clusters, subspace = 10, 4
+	// the clusters only depend on dims, so queries fall into the same clusters as the points
+	c := rand.New(rand.NewSource(int64(dims)))
+	centers := make([][]float64, clusters)
+	directions := make([][][]float64, clusters)
+	for i := range centers {
+		centers[i] = make([]float64, dims)
+		for d := range centers[i] {
+			centers[i][d] = c.Float64() * 100
+		}
+		directions[i] = make([][]float64, subspace)
+		for j := range directions[i] {
+			directions[i][j] = make([]float64, dims)
+			for d := range directions[i][j] {
+				directions[i][j][d] = c.NormFloat64()
+			}
+		}
+	}
+
+	points := make([]kdtree.Point, 0, n)
+	for i := 0; i < n; i++ {
+		cluster := r.Intn(clusters)
+		coordinates := append([]float64(nil), centers[cluster]...)
+		for _, direction := range directions[cluster] {
+			w := r.NormFloat64() * 2
+			for d := range coordinates {
+				coordinates[d] += w * direction[d]
+			}
+		}
+		points = append(points, NewPoint(coordinates, nil))
+	}
+	return points
+}
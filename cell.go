@@ -0,0 +1,95 @@
+/*
+ * Copyright 2020 Dennis Kuhnert
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package kdtree
+
+import "github.com/kyroy/kdtree/kdrange"
+
+// Cell is a read-only view of a node of the k-d tree and the subtree below it.
+// It allows algorithms outside of this package to traverse the tree and use its cached aggregates.
+//
+// The zero Cell represents an empty subtree. A Cell becomes invalid when the tree is modified.
+type Cell struct {
+	n *node
+}
+
+// Root returns the cell of the root node. It is empty if the tree is empty.
+func (t *KDTree) Root() Cell {
+	return Cell{n: t.root}
+}
+
+// IsEmpty reports whether the cell represents an empty subtree.
+func (c Cell) IsEmpty() bool {
+	return c.n == nil
+}
+
+// Point returns the point stored in the node. Returns nil for an empty cell.
+func (c Cell) Point() Point {
+	if c.n == nil {
+		return nil
+	}
+	return c.n.Point
+}
+
+// Axis returns the dimension that splits the subtree. Returns -1 for an empty cell.
+func (c Cell) Axis() int {
+	if c.n == nil {
+		return -1
+	}
+	return c.n.Axis
+}
+
+// Left returns the cell of the left child.
+func (c Cell) Left() Cell {
+	if c.n == nil {
+		return Cell{}
+	}
+	return Cell{n: c.n.Left}
+}
+
+// Right returns the cell of the right child.
+func (c Cell) Right() Cell {
+	if c.n == nil {
+		return Cell{}
+	}
+	return Cell{n: c.n.Right}
+}
+
+// Len returns the number of points in the subtree.
+func (c Cell) Len() int {
+	if c.n == nil {
+		return 0
+	}
+	return c.n.Size
+}
+
+// Bounds returns the bounding box of all points in the subtree.
+// The returned range must not be modified.
+func (c Cell) Bounds() kdrange.Range {
+	if c.n == nil {
+		return nil
+	}
+	return c.n.Bounds
+}
+
+// Sum returns the sum of the coordinates of all points in the subtree per dimension.
+// The returned slice must not be modified.
+func (c Cell) Sum() []float64 {
+	if c.n == nil {
+		return nil
+	}
+	return c.n.Sum
+}
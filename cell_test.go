@@ -0,0 +1,91 @@
+/*
+ * Copyright 2020 Dennis Kuhnert
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package kdtree_test
+
+import (
+	"github.com/kyroy/kdtree"
+	"github.com/kyroy/kdtree/kdrange"
+	. "github.com/kyroy/kdtree/points"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCell(t *testing.T) {
+	empty := kdtree.New(nil).Root()
+	assert.True(t, empty.IsEmpty())
+	assert.Nil(t, empty.Point())
+	assert.True(t, empty.Left().IsEmpty())
+	assert.True(t, empty.Right().IsEmpty())
+	assert.Equal(t, 0, empty.Len())
+	assert.Nil(t, empty.Bounds())
+	assert.Nil(t, empty.Sum())
+
+	tree := kdtree.New([]kdtree.Point{&Point2D{X: 1, Y: 5}, &Point2D{X: 2, Y: 1}, &Point2D{X: 3, Y: 3}})
+	root := tree.Root()
+	assert.False(t, root.IsEmpty())
+	assert.Equal(t, &Point2D{X: 2, Y: 1}, root.Point())
+	assert.Equal(t, &Point2D{X: 1, Y: 5}, root.Left().Point())
+	assert.Equal(t, &Point2D{X: 3, Y: 3}, root.Right().Point())
+	assert.Equal(t, 3, root.Len())
+	assert.Equal(t, kdrange.New(1, 3, 1, 5), root.Bounds())
+	assert.Equal(t, []float64{6, 9}, root.Sum())
+	assert.Equal(t, 1, root.Left().Len())
+	assert.True(t, root.Left().Left().IsEmpty())
+}
+
+func TestCellAfterInsertRemove(t *testing.T) {
+	input := generateTestCaseData(1000)
+	tree := kdtree.New(input[:500])
+	for _, p := range input[500:] {
+		tree.Insert(p)
+	}
+	for _, p := range input[:300] {
+		tree.Remove(p)
+	}
+	remaining := input[300:]
+
+	var cellPoints []kdtree.Point
+	var visit func(c kdtree.Cell)
+	visit = func(c kdtree.Cell) {
+		if c.IsEmpty() {
+			return
+		}
+		cellPoints = append(cellPoints, c.Point())
+		visit(c.Left())
+		visit(c.Right())
+	}
+	visit(tree.Root())
+	assert.ElementsMatch(t, remaining, cellPoints)
+
+	sum := []float64{0, 0}
+	for _, p := range remaining {
+		sum[0] += p.Dimension(0)
+		sum[1] += p.Dimension(1)
+	}
+	root := tree.Root()
+	assert.Equal(t, len(remaining), root.Len())
+	assert.InDeltaSlice(t, sum, root.Sum(), 1e-6)
+	assert.Equal(t, kdrange.BoundingRange(toRangePoints(remaining)...), root.Bounds())
+}
+
+func toRangePoints(input []kdtree.Point) []kdrange.Point {
+	result := make([]kdrange.Point, len(input))
+	for i, p := range input {
+		result[i] = p
+	}
+	return result
+}
@@ -0,0 +1,122 @@
+/*
+ * Copyright 2020 Dennis Kuhnert
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+// Package cluster implements density based clustering algorithms on top of the k-d tree.
+package cluster
+
+import (
+	"github.com/kyroy/kdtree"
+	"github.com/kyroy/kdtree/kdrange"
+	"math"
+)
+
+// Noise is the label of points that do not belong to any cluster.
+const Noise = -1
+
+const unclassified = -2
+
+// DBSCAN clusters the points with the DBSCAN algorithm.
+//
+// A point is a core point if at least minPts points (including itself) are within the distance eps.
+// Clusters are formed by core points that are within eps of each other and the points within eps of them.
+//
+// It returns a label for every point in the order of points: the cluster number starting at 0 or Noise.
+func DBSCAN(points []kdtree.Point, eps float64, minPts int) []int {
+	labels := make([]int, len(points))
+	for i := range labels {
+		labels[i] = unclassified
+	}
+	idx := newIndex(points)
+
+	cluster := 0
+	for i := range points {
+		if labels[i] != unclassified {
+			continue
+		}
+		neighbors := idx.neighbors(i, eps)
+		if len(neighbors) < minPts {
+			labels[i] = Noise
+			continue
+		}
+
+		labels[i] = cluster
+		for queue := neighbors; len(queue) > 0; queue = queue[1:] {
+			j := queue[0]
+			if labels[j] == Noise {
+				// border point
+				labels[j] = cluster
+			}
+			if labels[j] != unclassified {
+				continue
+			}
+			labels[j] = cluster
+			if n := idx.neighbors(j, eps); len(n) >= minPts {
+				queue = append(queue, n...)
+			}
+		}
+		cluster++
+	}
+	return labels
+}
+
+// index finds the neighbours of points by their position in the input slice.
+type index struct {
+	points []kdtree.Point
+	tree   *kdtree.KDTree
+}
+
+// indexedPoint remembers the position of a point in the input slice.
+type indexedPoint struct {
+	kdtree.Point
+	index int
+}
+
+func newIndex(points []kdtree.Point) *index {
+	indexed := make([]kdtree.Point, len(points))
+	for i, p := range points {
+		indexed[i] = &indexedPoint{Point: p, index: i}
+	}
+	return &index{
+		points: points,
+		tree:   kdtree.New(indexed),
+	}
+}
+
+// neighbors returns the positions of all points within the distance eps of the i-th point, including itself.
+func (idx *index) neighbors(i int, eps float64) []int {
+	p := idx.points[i]
+	center := make([]float64, p.Dimensions())
+	for dim := range center {
+		center[dim] = p.Dimension(dim)
+	}
+
+	var neighbors []int
+	idx.tree.RangeVisit(kdrange.NewBall(center, eps), func(o kdtree.Point) bool {
+		neighbors = append(neighbors, o.(*indexedPoint).index)
+		return true
+	})
+	return neighbors
+}
+
+// distance returns the distance between the i-th and the j-th point.
+func (idx *index) distance(i, j int) float64 {
+	sum := 0.
+	for dim := 0; dim < idx.points[i].Dimensions(); dim++ {
+		d := idx.points[i].Dimension(dim) - idx.points[j].Dimension(dim)
+		sum += d * d
+	}
+	return math.Sqrt(sum)
+}
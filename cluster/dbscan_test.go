@@ -0,0 +1,138 @@
+/*
+ * Copyright 2020 Dennis Kuhnert
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package cluster_test
+
+import (
+	"github.com/kyroy/kdtree"
+	"github.com/kyroy/kdtree/cluster"
+	. "github.com/kyroy/kdtree/points"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestDBSCAN(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  []kdtree.Point
+		eps    float64
+		minPts int
+		output []int
+	}{
+		{name: "nil", input: nil, eps: 1, minPts: 2, output: []int{}},
+		{
+			name:   "noise only",
+			input:  []kdtree.Point{&Point2D{X: 0, Y: 0}, &Point2D{X: 5, Y: 0}, &Point2D{X: 0, Y: 5}},
+			eps:    1,
+			minPts: 2,
+			output: []int{cluster.Noise, cluster.Noise, cluster.Noise},
+		},
+		{
+			name: "two clusters",
+			input: []kdtree.Point{
+				&Point2D{X: 0, Y: 0}, &Point2D{X: 1, Y: 0}, &Point2D{X: 0, Y: 1},
+				&Point2D{X: 50, Y: 50},
+				&Point2D{X: 10, Y: 10}, &Point2D{X: 10, Y: 11}, &Point2D{X: 11, Y: 11}, &Point2D{X: 12, Y: 11},
+			},
+			eps:    1,
+			minPts: 3,
+			output: []int{0, 0, 0, cluster.Noise, 1, 1, 1, 1},
+		},
+		{
+			name:   "border point",
+			input:  []kdtree.Point{&Point2D{X: 0, Y: 0}, &Point2D{X: 1, Y: 0}, &Point2D{X: -1, Y: 0}, &Point2D{X: 2, Y: 0}},
+			eps:    1,
+			minPts: 3,
+			output: []int{0, 0, 0, 0},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.output, cluster.DBSCAN(test.input, test.eps, test.minPts))
+		})
+	}
+}
+
+func TestDBSCANWithGenerator(t *testing.T) {
+	input := generateBlobs(5, 200, 1000)
+	eps, minPts := 15., 5
+	labels := cluster.DBSCAN(input, eps, minPts)
+
+	core := bruteForceCorePoints(input, eps, minPts)
+	for i, p := range input {
+		if core[i] {
+			assert.NotEqual(t, cluster.Noise, labels[i])
+			// core points within eps belong to the same cluster
+			for j, o := range input {
+				if core[j] && distance(p, o) <= eps {
+					assert.Equal(t, labels[i], labels[j])
+				}
+			}
+			continue
+		}
+		// non-core points are border points of a neighbouring core point's cluster or noise
+		expected := cluster.Noise
+		for j, o := range input {
+			if core[j] && distance(p, o) <= eps && labels[j] == labels[i] {
+				expected = labels[i]
+			}
+		}
+		assert.Equal(t, expected, labels[i])
+	}
+}
+
+// generateBlobs generates clusters of normally distributed points and uniformly distributed noise.
+func generateBlobs(clusters, size, noise int) []kdtree.Point {
+	r := rand.New(rand.NewSource(42))
+	var points []kdtree.Point
+	for c := 0; c < clusters; c++ {
+		cx, cy := r.Float64()*3000-1500, r.Float64()*3000-1500
+		for i := 0; i < size; i++ {
+			points = append(points, &Point2D{X: cx + r.NormFloat64()*30, Y: cy + r.NormFloat64()*30})
+		}
+	}
+	for i := 0; i < noise; i++ {
+		points = append(points, &Point2D{X: r.Float64()*3000 - 1500, Y: r.Float64()*3000 - 1500})
+	}
+	r.Shuffle(len(points), func(i, j int) {
+		points[i], points[j] = points[j], points[i]
+	})
+	return points
+}
+
+func bruteForceCorePoints(points []kdtree.Point, eps float64, minPts int) []bool {
+	core := make([]bool, len(points))
+	for i, p := range points {
+		count := 0
+		for _, o := range points {
+			if distance(p, o) <= eps {
+				count++
+			}
+		}
+		core[i] = count >= minPts
+	}
+	return core
+}
+
+func distance(p1, p2 kdtree.Point) float64 {
+	sum := 0.
+	for i := 0; i < p1.Dimensions(); i++ {
+		sum += math.Pow(p1.Dimension(i)-p2.Dimension(i), 2.0)
+	}
+	return math.Sqrt(sum)
+}
@@ -0,0 +1,141 @@
+/*
+ * Copyright 2020 Dennis Kuhnert
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package cluster
+
+import (
+	"container/heap"
+	"github.com/kyroy/kdtree"
+	"math"
+	"sort"
+)
+
+// OPTICSPoint is an entry of the cluster ordering computed by OPTICS.
+type OPTICSPoint struct {
+	// Index is the position of the point in the input slice.
+	Index int
+	// Reachability is the reachability distance of the point, +Inf if it is undefined.
+	Reachability float64
+	// CoreDistance is the distance to the minPts-th nearest neighbour, +Inf if the point is not a core point.
+	CoreDistance float64
+}
+
+// OPTICS computes the cluster ordering of the points with the OPTICS algorithm.
+//
+// Valleys of low reachability distances in the ordering are clusters. Unlike DBSCAN the ordering
+// contains the clusters of all distances up to eps, see ExtractDBSCAN.
+func OPTICS(points []kdtree.Point, eps float64, minPts int) []OPTICSPoint {
+	idx := newIndex(points)
+	processed := make([]bool, len(points))
+	reachability := make([]float64, len(points))
+	for i := range reachability {
+		reachability[i] = math.Inf(1)
+	}
+
+	ordering := make([]OPTICSPoint, 0, len(points))
+	for i := range points {
+		if processed[i] {
+			continue
+		}
+		seeds := &seedQueue{{index: i, reachability: math.Inf(1)}}
+		for seeds.Len() > 0 {
+			j := heap.Pop(seeds).(seed).index
+			if processed[j] {
+				// outdated entry with a higher reachability
+				continue
+			}
+			processed[j] = true
+
+			neighbors := idx.neighbors(j, eps)
+			distances := make([]float64, len(neighbors))
+			for n, o := range neighbors {
+				distances[n] = idx.distance(j, o)
+			}
+			core := coreDistance(distances, minPts)
+			ordering = append(ordering, OPTICSPoint{Index: j, Reachability: reachability[j], CoreDistance: core})
+			if math.IsInf(core, 1) {
+				continue
+			}
+
+			for n, o := range neighbors {
+				if processed[o] {
+					continue
+				}
+				if r := math.Max(core, distances[n]); r < reachability[o] {
+					reachability[o] = r
+					heap.Push(seeds, seed{index: o, reachability: r})
+				}
+			}
+		}
+	}
+	return ordering
+}
+
+// ExtractDBSCAN extracts DBSCAN clusters with the distance eps from an OPTICS ordering that was computed with a distance >= eps.
+//
+// It returns a label for every point in the order of the input slice of OPTICS: the cluster number starting at 0 or Noise.
+// The clusters equal the ones of DBSCAN except for border points, which are reachable from multiple clusters.
+func ExtractDBSCAN(ordering []OPTICSPoint, eps float64) []int {
+	labels := make([]int, len(ordering))
+	cluster := Noise
+	for _, o := range ordering {
+		if o.Reachability > eps {
+			if o.CoreDistance > eps {
+				labels[o.Index] = Noise
+				continue
+			}
+			cluster++
+		}
+		labels[o.Index] = cluster
+	}
+	return labels
+}
+
+// coreDistance returns the minPts-th smallest distance or +Inf if there are less than minPts distances.
+func coreDistance(distances []float64, minPts int) float64 {
+	if minPts < 1 {
+		return 0
+	}
+	if len(distances) < minPts {
+		return math.Inf(1)
+	}
+	sorted := append([]float64(nil), distances...)
+	sort.Float64s(sorted)
+	return sorted[minPts-1]
+}
+
+type seed struct {
+	index        int
+	reachability float64
+}
+
+// seedQueue implements heap.Interface ordered by the reachability distance.
+type seedQueue []seed
+
+func (q seedQueue) Len() int           { return len(q) }
+func (q seedQueue) Less(i, j int) bool { return q[i].reachability < q[j].reachability }
+func (q seedQueue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+
+func (q *seedQueue) Push(x interface{}) {
+	*q = append(*q, x.(seed))
+}
+
+func (q *seedQueue) Pop() interface{} {
+	old := *q
+	x := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return x
+}
@@ -0,0 +1,76 @@
+/*
+ * Copyright 2020 Dennis Kuhnert
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package cluster_test
+
+import (
+	"github.com/kyroy/kdtree"
+	"github.com/kyroy/kdtree/cluster"
+	. "github.com/kyroy/kdtree/points"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+func TestOPTICS(t *testing.T) {
+	input := []kdtree.Point{&Point2D{X: 0, Y: 0}, &Point2D{X: 1, Y: 0}, &Point2D{X: 3, Y: 0}, &Point2D{X: 100, Y: 0}}
+	ordering := cluster.OPTICS(input, 5, 2)
+	assert.Equal(t, []cluster.OPTICSPoint{
+		{Index: 0, Reachability: math.Inf(1), CoreDistance: 1},
+		{Index: 1, Reachability: 1, CoreDistance: 1},
+		{Index: 2, Reachability: 2, CoreDistance: 2},
+		{Index: 3, Reachability: math.Inf(1), CoreDistance: math.Inf(1)},
+	}, ordering)
+
+	assert.Equal(t, []int{0, 0, 0, cluster.Noise}, cluster.ExtractDBSCAN(ordering, 5))
+	assert.Equal(t, []int{0, 0, cluster.Noise, cluster.Noise}, cluster.ExtractDBSCAN(ordering, 1))
+}
+
+func TestExtractDBSCANWithGenerator(t *testing.T) {
+	input := generateBlobs(5, 200, 1000)
+	ordering := cluster.OPTICS(input, 40, 5)
+	assert.Len(t, ordering, len(input))
+
+	for _, eps := range []float64{10, 15, 40} {
+		labels := cluster.ExtractDBSCAN(ordering, eps)
+		expected := cluster.DBSCAN(input, eps, 5)
+		core := bruteForceCorePoints(input, eps, 5)
+
+		// core points are clustered identically up to the cluster numbers
+		mapping := make(map[int]int)
+		for i := range input {
+			if !core[i] {
+				continue
+			}
+			if m, ok := mapping[expected[i]]; ok {
+				assert.Equal(t, m, labels[i])
+			} else {
+				mapping[expected[i]] = labels[i]
+			}
+		}
+		assert.Len(t, mapping, len(uniqueLabels(expected)))
+	}
+}
+
+func uniqueLabels(labels []int) map[int]bool {
+	unique := make(map[int]bool)
+	for _, l := range labels {
+		if l != cluster.Noise {
+			unique[l] = true
+		}
+	}
+	return unique
+}
@@ -0,0 +1,216 @@
+/*
+ * Copyright 2020 Dennis Kuhnert
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package kdtree
+
+import (
+	"math"
+	"sort"
+)
+
+// EMST returns the edges of the Euclidean minimum spanning tree of the points of the tree.
+// A tree with n points results in n-1 edges, sorted by the distance. Starting with the shortest.
+//
+// The edges are computed with the dual-tree Borůvka algorithm: in every round, each component
+// searches its nearest neighbour in another component with a simultaneous traversal of the tree,
+// and all components are connected with their nearest neighbour.
+//
+// Returns an empty slice when the tree contains less than two points.
+func (t *KDTree) EMST() []Pair {
+	edges := []Pair{}
+	if t.root == nil || t.root.Size < 2 {
+		return edges
+	}
+
+	b := &boruvka{parent: make(map[*node]*node, t.root.Size)}
+	t.root.visitNodes(func(n *node) {
+		b.parent[n] = n
+	})
+	for len(edges) < t.root.Size-1 {
+		b.component = make(map[*node]*node, t.root.Size)
+		b.subtree = make(map[*node]*node, t.root.Size)
+		b.nearest = make(map[*node]edge)
+		b.bounds = make(map[*node]float64, t.root.Size)
+		b.prepare(t.root)
+		b.join(t.root, t.root)
+
+		// connect the components in the order of the distance, two components may have found the same edge
+		candidates := make([]edge, 0, len(b.nearest))
+		for _, e := range b.nearest {
+			candidates = append(candidates, e)
+		}
+		sort.Slice(candidates, func(i, j int) bool {
+			return candidates[i].sqDistance < candidates[j].sqDistance
+		})
+		connected := false
+		for _, e := range candidates {
+			if e.a != nil && b.union(e.a, e.b) {
+				edges = append(edges, Pair{A: e.a.Point, B: e.b.Point, Distance: math.Sqrt(e.sqDistance)})
+				connected = true
+			}
+		}
+		if !connected {
+			// only possible for points with NaN coordinates
+			break
+		}
+	}
+
+	sort.Slice(edges, func(i, j int) bool {
+		return edges[i].Distance < edges[j].Distance
+	})
+	return edges
+}
+
+// edge is a candidate edge of the minimum spanning tree.
+type edge struct {
+	a, b       *node
+	sqDistance float64
+}
+
+// boruvka holds the state of the dual-tree Borůvka algorithm.
+type boruvka struct {
+	// parent is the union-find forest of the components.
+	parent map[*node]*node
+	// component contains the component of every node at the beginning of the round.
+	component map[*node]*node
+	// subtree contains the component of all nodes in a subtree if they belong to the same one, nil otherwise.
+	subtree map[*node]*node
+	// nearest contains the shortest edge found so far that leaves a component.
+	nearest map[*node]edge
+	// bounds contains the largest squared distance of the nearest edges of all nodes in a subtree.
+	bounds map[*node]float64
+}
+
+func (b *boruvka) find(n *node) *node {
+	for b.parent[n] != n {
+		b.parent[n] = b.parent[b.parent[n]]
+		n = b.parent[n]
+	}
+	return n
+}
+
+// union merges the components of a and b. Returns false if they already are the same.
+func (b *boruvka) union(x, y *node) bool {
+	x, y = b.find(x), b.find(y)
+	if x == y {
+		return false
+	}
+	b.parent[x] = y
+	return true
+}
+
+// prepare computes the components of the subtree n for the next round.
+func (b *boruvka) prepare(n *node) {
+	c := b.find(n)
+	b.component[n] = c
+	b.nearest[c] = edge{sqDistance: math.Inf(1)}
+	b.bounds[n] = math.Inf(1)
+
+	subtree := c
+	for _, child := range []*node{n.Left, n.Right} {
+		if child != nil {
+			b.prepare(child)
+			if b.subtree[child] != c {
+				subtree = nil
+			}
+		}
+	}
+	b.subtree[n] = subtree
+}
+
+// join searches the nearest edges from the nodes of subtree x to the nodes of subtree y.
+func (b *boruvka) join(x, y *node) {
+	if (b.subtree[x] != nil && b.subtree[x] == b.subtree[y]) || boxSquaredDistance(x.Bounds, y.Bounds) > b.bounds[x] {
+		return
+	}
+
+	// split the larger subtree
+	if x.Size >= y.Size {
+		b.point(x, y)
+		first, second := nearerChild(x, y)
+		if first != nil {
+			b.join(first, y)
+		}
+		if second != nil {
+			b.join(second, y)
+		}
+	} else {
+		b.reverse(x, y)
+		first, second := nearerChild(y, x)
+		if first != nil {
+			b.join(x, first)
+		}
+		if second != nil {
+			b.join(x, second)
+		}
+	}
+	b.updateBound(x)
+}
+
+// point searches the nearest edge from the single node p to the subtree n.
+func (b *boruvka) point(p, n *node) {
+	c := b.component[p]
+	if b.subtree[n] == c || minSquaredDistance(p, n.Bounds) > b.nearest[c].sqDistance {
+		return
+	}
+
+	if b.component[n] != c {
+		if d := squaredDistance(p, n); d < b.nearest[c].sqDistance {
+			b.nearest[c] = edge{a: p, b: n, sqDistance: d}
+		}
+	}
+	first, second := n.Left, n.Right
+	if first == nil || (second != nil && minSquaredDistance(p, second.Bounds) < minSquaredDistance(p, first.Bounds)) {
+		first, second = second, first
+	}
+	if first != nil {
+		b.point(p, first)
+	}
+	if second != nil {
+		b.point(p, second)
+	}
+}
+
+// reverse offers the single node o as nearest edge to all nodes of the subtree n.
+func (b *boruvka) reverse(n, o *node) {
+	if b.subtree[n] == b.component[o] || minSquaredDistance(o, n.Bounds) > b.bounds[n] {
+		return
+	}
+
+	if c := b.component[n]; c != b.component[o] {
+		if d := squaredDistance(n, o); d < b.nearest[c].sqDistance {
+			b.nearest[c] = edge{a: n, b: o, sqDistance: d}
+		}
+	}
+	if n.Left != nil {
+		b.reverse(n.Left, o)
+	}
+	if n.Right != nil {
+		b.reverse(n.Right, o)
+	}
+	b.updateBound(n)
+}
+
+func (b *boruvka) updateBound(n *node) {
+	bound := b.nearest[b.component[n]].sqDistance
+	if n.Left != nil {
+		bound = math.Max(bound, b.bounds[n.Left])
+	}
+	if n.Right != nil {
+		bound = math.Max(bound, b.bounds[n.Right])
+	}
+	b.bounds[n] = bound
+}
@@ -0,0 +1,145 @@
+/*
+ * Copyright 2020 Dennis Kuhnert
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package kdtree_test
+
+import (
+	"github.com/kyroy/kdtree"
+	. "github.com/kyroy/kdtree/points"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"sort"
+	"testing"
+)
+
+func TestKDTree_EMST(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     []kdtree.Point
+		distances []float64
+	}{
+		{name: "empty", input: nil, distances: []float64{}},
+		{name: "1", input: []kdtree.Point{&Point2D{X: 1, Y: 2}}, distances: []float64{}},
+		{name: "2", input: []kdtree.Point{&Point2D{X: 1, Y: 2}, &Point2D{X: 4, Y: 6}}, distances: []float64{5}},
+		{
+			name:      "duplicates",
+			input:     []kdtree.Point{&Point2D{X: 1, Y: 1}, &Point2D{X: 1, Y: 1}, &Point2D{X: 1, Y: 1}},
+			distances: []float64{0, 0},
+		},
+		{
+			name:      "line",
+			input:     []kdtree.Point{&Point2D{X: 0, Y: 0}, &Point2D{X: 6, Y: 0}, &Point2D{X: 1, Y: 0}, &Point2D{X: 3, Y: 0}, &Point2D{X: 10, Y: 0}},
+			distances: []float64{1, 2, 3, 4},
+		},
+		{
+			name:      "square with center",
+			input:     []kdtree.Point{&Point2D{X: 0, Y: 0}, &Point2D{X: 2, Y: 0}, &Point2D{X: 0, Y: 2}, &Point2D{X: 2, Y: 2}, &Point2D{X: 1, Y: 1}},
+			distances: []float64{math.Sqrt2, math.Sqrt2, math.Sqrt2, math.Sqrt2},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			edges := kdtree.New(test.input).EMST()
+			distances := make([]float64, 0, len(edges))
+			for _, e := range edges {
+				distances = append(distances, e.Distance)
+			}
+			assert.Equal(t, test.distances, distances)
+			assertSpanning(t, test.input, edges)
+		})
+	}
+}
+
+func TestKDTree_EMSTWithGenerator(t *testing.T) {
+	for _, dims := range []int{2, 3} {
+		for _, size := range []int{10, 100, 1000} {
+			input := make([]kdtree.Point, 0, size)
+			for i := 0; i < size; i++ {
+				input = append(input, generateTestPoint(dims))
+			}
+			edges := kdtree.New(append([]kdtree.Point(nil), input...)).EMST()
+
+			expected := primDistances(input)
+			assert.Len(t, edges, len(expected))
+			for i, e := range edges {
+				assert.InDelta(t, expected[i], e.Distance, 1e-9)
+				assert.InDelta(t, distance(e.A, e.B), e.Distance, 1e-9)
+			}
+			assertSpanning(t, input, edges)
+		}
+	}
+}
+
+func BenchmarkKDTree_EMST(b *testing.B) {
+	tree := kdtree.New(generateTestCaseData(10000))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tree.EMST()
+	}
+}
+
+// assertSpanning asserts that the edges connect all points.
+func assertSpanning(t *testing.T, input []kdtree.Point, edges []kdtree.Pair) {
+	parent := make(map[kdtree.Point]kdtree.Point, len(input))
+	var find func(p kdtree.Point) kdtree.Point
+	find = func(p kdtree.Point) kdtree.Point {
+		if parent[p] == nil || parent[p] == p {
+			return p
+		}
+		return find(parent[p])
+	}
+	components := len(input)
+	for _, e := range edges {
+		if a, b := find(e.A), find(e.B); a != b {
+			parent[a] = b
+			components--
+		}
+	}
+	if len(input) > 0 {
+		assert.Equal(t, 1, components)
+	}
+}
+
+// primDistances returns the sorted edge distances of the minimum spanning tree.
+func primDistances(input []kdtree.Point) []float64 {
+	result := []float64{}
+	if len(input) == 0 {
+		return result
+	}
+	inTree := make([]bool, len(input))
+	nearest := make([]float64, len(input))
+	for i := range nearest {
+		nearest[i] = math.Inf(1)
+	}
+	current := 0
+	for range input[1:] {
+		inTree[current] = true
+		next := -1
+		for i, p := range input {
+			if inTree[i] {
+				continue
+			}
+			nearest[i] = math.Min(nearest[i], distance(input[current], p))
+			if next < 0 || nearest[i] < nearest[next] {
+				next = i
+			}
+		}
+		result = append(result, nearest[next])
+		current = next
+	}
+	sort.Float64s(result)
+	return result
+}
@@ -0,0 +1,62 @@
+/*
+ * Copyright 2020 Dennis Kuhnert
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package kdtree
+
+import "math"
+
+// Node exposes the nodes of the tree to tests that need to modify them.
+type Node = node
+
+// RootNode returns the root node of the tree.
+func RootNode(t *KDTree) *Node {
+	return t.root
+}
+
+// KNNVisited returns the number of nodes visited by KNN.
+func KNNVisited(t *KDTree, p Point, k int) int {
+	s := newKNNSearch(p, k)
+	s.search(t.root, 1)
+	return s.stats.NodesVisited
+}
+
+// PlaneKNNVisited returns the number of nodes visited by KNN if the subtrees were only pruned
+// with the distance to the splitting plane instead of their bounding box.
+func PlaneKNNVisited(t *KDTree, p Point, k int) int {
+	s := newKNNSearch(p, k)
+	s.planeSearch(t.root)
+	return s.stats.NodesVisited
+}
+
+func (s *knnSearch) planeSearch(n *node) {
+	if n == nil {
+		return
+	}
+
+	near, far := n.Right, n.Left
+	if s.p.Dimension(n.Axis) < n.Dimension(n.Axis) {
+		near, far = far, near
+	}
+	s.planeSearch(near)
+
+	s.stats.NodesVisited++
+	if d := distance(s.p, n); d < getKthOrLastDistance(s.nearest, s.k-1) {
+		s.nearest.Insert(n, d)
+	}
+	if math.Abs(s.p.Dimension(n.Axis)-n.Dimension(n.Axis)) < getKthOrLastDistance(s.nearest, s.k-1) {
+		s.planeSearch(far)
+	}
+}
@@ -0,0 +1,102 @@
+//go:build go1.18
+// +build go1.18
+
+/*
+ * Copyright 2020 Dennis Kuhnert
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package kdtree_test
+
+import (
+	"github.com/kyroy/kdtree"
+	"github.com/kyroy/kdtree/kdrange"
+	"github.com/kyroy/kdtree/kdtreetest"
+	. "github.com/kyroy/kdtree/points"
+	"testing"
+)
+
+// The fuzz targets need testing.F, so this file is only built with Go 1.18 or later.
+// Older versions still run all other tests of the package.
+
+// FuzzKDTree interprets the input as a sequence of operations on a tree and compares the tree with a brute force search.
+// The first byte selects the splitter, then every 3 bytes form an operation and the coordinates of a point.
+// The coordinates are taken from a small grid to provoke duplicates.
+//
+// Run it with: go test -run XXX -fuzz FuzzKDTree
+func FuzzKDTree(f *testing.F) {
+	f.Add([]byte{0, 0, 1, 2, 0, 3, 4, 1, 1, 2, 3, 1, 1, 4, 0, 5})
+	f.Add([]byte{1, 0, 5, 5, 0, 5, 5, 0, 5, 5, 2, 5, 5, 3, 5, 2, 5, 0, 0})
+	f.Add([]byte{2, 0, 1, 1, 0, 2, 2, 0, 3, 3, 0, 4, 4, 5, 0, 0, 2, 3, 3, 4, 0, 9})
+	f.Add([]byte{3, 0, 9, 1, 0, 1, 9, 0, 8, 8, 0, 2, 2, 2, 9, 1, 5, 0, 0, 3, 4, 4})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		if len(data) == 0 {
+			return
+		}
+		splitter := kdtreetest.Splitters[int(data[0])%len(kdtreetest.Splitters)].Splitter
+		tree := kdtree.New(nil, kdtree.WithSplitter(splitter))
+		var points []kdtree.Point
+
+		for data = data[1:]; len(data) >= 3; data = data[3:] {
+			p := &Point2D{X: float64(data[1] % 16), Y: float64(data[2] % 16)}
+			switch data[0] % 6 {
+			case 0, 1:
+				tree.Insert(p)
+				points = append(points, p)
+			case 2:
+				points = kdtreetest.Remove(t, tree, points, p)
+			case 3:
+				k := int(data[2]%8) + 1
+				kdtreetest.AssertSameDistances(t, p, kdtreetest.BruteForceKNN(points, p, k), tree.KNN(p, k))
+			case 4:
+				r := kdrange.New(p.X, p.X+float64(data[2]%8), p.Y-float64(data[1]%8), p.Y)
+				kdtreetest.AssertSamePoints(t, kdtreetest.BruteForceRange(points, r), tree.RangeSearch(r))
+			case 5:
+				tree = kdtree.New(tree.Points(), kdtree.WithSplitter(splitter))
+			}
+
+			if err := tree.Validate(); err != nil {
+				t.Fatal(err)
+			}
+			if tree.Len() != len(points) {
+				t.Fatalf("Len() = %d, expected %d", tree.Len(), len(points))
+			}
+		}
+		kdtreetest.AssertSamePoints(t, points, tree.Points())
+	})
+}
+
+// FuzzNew builds a tree from the coordinates of the input and compares it with a brute force search.
+//
+// Run it with: go test -run XXX -fuzz FuzzNew
+func FuzzNew(f *testing.F) {
+	f.Add([]byte{1, 2, 3, 4, 5, 6, 7, 8, 9}, uint8(0))
+	f.Add([]byte{1, 1, 1, 1, 1, 1, 2, 2, 2}, uint8(3))
+
+	f.Fuzz(func(t *testing.T, data []byte, s uint8) {
+		var points []kdtree.Point
+		for ; len(data) >= 3; data = data[3:] {
+			points = append(points, &Point3D{X: float64(data[0]), Y: float64(data[1] % 4), Z: float64(data[2])})
+		}
+		tree := kdtree.New(append([]kdtree.Point(nil), points...), kdtree.WithSplitter(kdtreetest.Splitters[int(s)%len(kdtreetest.Splitters)].Splitter))
+		if err := tree.Validate(); err != nil {
+			t.Fatal(err)
+		}
+		kdtreetest.AssertSamePoints(t, points, tree.Points())
+		for _, p := range points {
+			kdtreetest.AssertSameDistances(t, p, kdtreetest.BruteForceKNN(points, p, 3), tree.KNN(p, 3))
+		}
+	})
+}
@@ -0,0 +1,161 @@
+/*
+ * Copyright 2020 Dennis Kuhnert
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package kdtree
+
+import (
+	"github.com/kyroy/kdtree/kdrange"
+	"sort"
+)
+
+// Index is a spatial index of points. It is implemented by KDTree and LinearIndex.
+type Index interface {
+	// Insert adds a point to the index.
+	Insert(p Point)
+	// Remove removes and returns the first point from the index that equals the given point p in all dimensions.
+	// Returns nil if not found.
+	Remove(p Point) Point
+	// KNN returns the k-nearest neighbours of the given point, sorted by the distance. Starting with the nearest.
+	KNN(p Point, k int, options ...QueryOption) []Point
+	// RangeSearch returns all points in the given region r.
+	RangeSearch(r kdrange.Region, options ...QueryOption) []Point
+	// Points returns all points of the index. The order depends on the implementation.
+	Points() []Point
+}
+
+var (
+	_ Index = (*KDTree)(nil)
+	_ Index = (*LinearIndex)(nil)
+)
+
+// LinearIndex is an Index that scans all points for every query.
+// It is exact and simple, which makes it suitable for small sets of points and as reference in tests.
+type LinearIndex struct {
+	points []Point
+}
+
+// NewLinearIndex returns a LinearIndex containing the given points.
+func NewLinearIndex(points []Point) *LinearIndex {
+	return &LinearIndex{points: append([]Point(nil), points...)}
+}
+
+// Insert adds a point to the index.
+func (l *LinearIndex) Insert(p Point) {
+	l.points = append(l.points, p)
+}
+
+// Remove removes and returns the first point from the index that equals the given point p in all dimensions.
+// Returns nil if not found.
+func (l *LinearIndex) Remove(p Point) Point {
+	if p == nil {
+		return nil
+	}
+	for i, o := range l.points {
+		if o.Dimensions() == p.Dimensions() && equalPoints(o, p) {
+			l.points = append(l.points[:i], l.points[i+1:]...)
+			return o
+		}
+	}
+	return nil
+}
+
+// KNN returns the k-nearest neighbours of the given point.
+// The points are sorted by the distance to the given points. Starting with the nearest.
+// Points with the same distance are sorted in the order they were added.
+//
+// Statistics about the search are collected with WithQueryStats.
+func (l *LinearIndex) KNN(p Point, k int, options ...QueryOption) []Point {
+	if p == nil || k <= 0 {
+		return []Point{}
+	}
+
+	var stats QueryStats
+	distances := make([]float64, len(l.points))
+	sorted := make([]Point, len(l.points))
+	for i, o := range l.points {
+		stats.visit(1)
+		stats.DistanceComputations++
+		distances[i] = distance(p, o)
+		sorted[i] = o
+	}
+	newQuery(options).finish(&stats)
+
+	sort.Stable(&byDistance{points: sorted, distances: distances})
+	if k < len(sorted) {
+		sorted = sorted[:k]
+	}
+	return sorted
+}
+
+// RangeSearch returns all points in the given region r in the order they were added.
+//
+// Statistics about the search are collected with WithQueryStats.
+//
+// Returns an empty slice when input is nil or r does not match the points' dimensions.
+func (l *LinearIndex) RangeSearch(r kdrange.Region, options ...QueryOption) []Point {
+	points := []Point{}
+	if r == nil {
+		return points
+	}
+
+	var stats QueryStats
+	for _, p := range l.points {
+		stats.visit(1)
+		if r.Contains(p) {
+			points = append(points, p)
+		}
+	}
+	newQuery(options).finish(&stats)
+	return points
+}
+
+// Points returns all points in the order they were added.
+func (l *LinearIndex) Points() []Point {
+	return append([]Point{}, l.points...)
+}
+
+// Len returns the number of points in the index.
+func (l *LinearIndex) Len() int {
+	return len(l.points)
+}
+
+func equalPoints(a, b Point) bool {
+	for i := 0; i < a.Dimensions(); i++ {
+		if a.Dimension(i) != b.Dimension(i) {
+			return false
+		}
+	}
+	return true
+}
+
+// byDistance sorts points by their distances.
+type byDistance struct {
+	points    []Point
+	distances []float64
+}
+
+func (b *byDistance) Len() int {
+	return len(b.points)
+}
+
+func (b *byDistance) Less(i, j int) bool {
+	return b.distances[i] < b.distances[j]
+}
+
+func (b *byDistance) Swap(i, j int) {
+	b.points[i], b.points[j] = b.points[j], b.points[i]
+	b.distances[i], b.distances[j] = b.distances[j], b.distances[i]
+}
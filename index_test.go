@@ -0,0 +1,92 @@
+/*
+ * Copyright 2020 Dennis Kuhnert
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package kdtree_test
+
+import (
+	"github.com/kyroy/kdtree"
+	"github.com/kyroy/kdtree/kdrange"
+	"github.com/kyroy/kdtree/kdtreetest"
+	. "github.com/kyroy/kdtree/points"
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"testing"
+)
+
+func TestLinearIndex(t *testing.T) {
+	input := []kdtree.Point{&Point2D{X: 3, Y: 0}, &Point2D{X: 1, Y: 0}, &Point2D{X: -1, Y: 0}, &Point2D{X: 2, Y: 2}}
+	index := kdtree.NewLinearIndex(input)
+	input[0] = nil
+	assert.Equal(t, 4, index.Len())
+
+	assert.Equal(t, []kdtree.Point{}, index.KNN(nil, 2))
+	assert.Equal(t, []kdtree.Point{}, index.KNN(&Point2D{}, 0))
+	// equal distances keep the order of insertion
+	assert.Equal(t, []kdtree.Point{&Point2D{X: 1, Y: 0}, &Point2D{X: -1, Y: 0}, &Point2D{X: 2, Y: 2}}, index.KNN(&Point2D{}, 3))
+	assert.Len(t, index.KNN(&Point2D{}, 10), 4)
+
+	assert.Equal(t, []kdtree.Point{}, index.RangeSearch(nil))
+	assert.Equal(t, []kdtree.Point{&Point2D{X: 3, Y: 0}, &Point2D{X: 1, Y: 0}}, index.RangeSearch(kdrange.New(0, 5, -1, 1)))
+	assert.Equal(t, []kdtree.Point{}, index.RangeSearch(kdrange.New(0, 5)))
+
+	var stats kdtree.QueryStats
+	index.KNN(&Point2D{}, 1, kdtree.WithQueryStats(&stats))
+	index.RangeSearch(kdrange.New(0, 5, -1, 1), kdtree.WithQueryStats(&stats))
+	assert.Equal(t, kdtree.QueryStats{NodesVisited: 8, DistanceComputations: 4, MaxDepth: 1}, stats)
+
+	assert.Nil(t, index.Remove(nil))
+	assert.Nil(t, index.Remove(&Point2D{X: 5, Y: 5}))
+	assert.Nil(t, index.Remove(&Point3D{X: 1}))
+	assert.Equal(t, &Point2D{X: 1, Y: 0}, index.Remove(&Point2D{X: 1, Y: 0}))
+	index.Insert(&Point2D{X: 0, Y: 0})
+	assert.Equal(t, []kdtree.Point{&Point2D{X: 3, Y: 0}, &Point2D{X: -1, Y: 0}, &Point2D{X: 2, Y: 2}, &Point2D{X: 0, Y: 0}}, index.Points())
+}
+
+func TestIndexWithGenerator(t *testing.T) {
+	indexes := map[string]func(points []kdtree.Point) kdtree.Index{
+		"KDTree": func(points []kdtree.Point) kdtree.Index {
+			return kdtree.New(points)
+		},
+		"KDTree sliding midpoint": func(points []kdtree.Point) kdtree.Index {
+			return kdtree.New(points, kdtree.WithSplitter(kdtree.SlidingMidpoint))
+		},
+		"LinearIndex": func(points []kdtree.Point) kdtree.Index {
+			return kdtree.NewLinearIndex(points)
+		},
+	}
+	for name, newIndex := range indexes {
+		t.Run(name, func(t *testing.T) {
+			input := generateTestCaseData(1000)
+			index := newIndex(append([]kdtree.Point(nil), input[:500]...))
+			oracle := kdtree.NewLinearIndex(input[:500])
+			for i, p := range input[500:] {
+				index.Insert(p)
+				oracle.Insert(p)
+				if i%3 == 0 {
+					removed := input[rand.Intn(500+i)]
+					assert.Equal(t, oracle.Remove(removed), index.Remove(removed))
+				}
+				if i%50 == 0 {
+					q := generateTestPoint(2)
+					kdtreetest.AssertSameDistances(t, q, oracle.KNN(q, 10), index.KNN(q, 10))
+					r := kdrange.New(-500, 500, -200, 700)
+					kdtreetest.AssertSamePoints(t, oracle.RangeSearch(r), index.RangeSearch(r))
+				}
+			}
+			kdtreetest.AssertSamePoints(t, oracle.Points(), index.Points())
+		})
+	}
+}
@@ -0,0 +1,188 @@
+/*
+ * Copyright 2020 Dennis Kuhnert
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package kdtree
+
+import (
+	"github.com/kyroy/priority-queue"
+	"math"
+	"sort"
+)
+
+// KNNJoin returns for every point of the tree a its k-nearest neighbours in the tree b.
+// The pairs are grouped by their point of a (Pair.A). Within a group they are sorted by the distance. Starting with the nearest.
+//
+// Both trees are traversed simultaneously, so pairs of subtrees that are too far apart are pruned as a whole.
+//
+// Returns an empty slice when a tree is empty or their dimensions differ.
+func KNNJoin(a, b *KDTree, k int) []Pair {
+	pairs := []Pair{}
+	if !joinable(a, b) || k <= 0 {
+		return pairs
+	}
+
+	j := &knnJoin{
+		k:       k,
+		nearest: make(map[*node]*pq.PriorityQueue, a.root.Size),
+		bounds:  make(map[*node]float64, a.root.Size),
+	}
+	var nodes []*node
+	a.root.visitNodes(func(n *node) {
+		j.nearest[n] = pq.NewPriorityQueue(pq.WithMinPrioSize(k))
+		j.bounds[n] = math.Inf(1)
+		nodes = append(nodes, n)
+	})
+	j.join(a.root, b.root)
+
+	for _, n := range nodes {
+		nearestPQ := j.nearest[n]
+		for nearestPQ.Len() > 0 {
+			o, d := nearestPQ.Get(0)
+			nearestPQ.PopLowest()
+			pairs = append(pairs, Pair{A: n.Point, B: o.(*node).Point, Distance: d})
+		}
+	}
+	return pairs
+}
+
+// DistanceJoin returns all pairs of a point of the tree a and a point of the tree b with a distance of at most d.
+// The pairs are sorted by the distance. Starting with the closest.
+//
+// Both trees are traversed simultaneously, so pairs of subtrees that are too far apart are pruned as a whole.
+//
+// Returns an empty slice when a tree is empty, their dimensions differ or d is negative.
+func DistanceJoin(a, b *KDTree, d float64) []Pair {
+	pairs := []Pair{}
+	if !joinable(a, b) || d < 0 {
+		return pairs
+	}
+
+	s := &pairSearch{sqBound: d * d}
+	s.visit = func(x, y *node, sqDistance float64) {
+		pairs = append(pairs, Pair{A: x.Point, B: y.Point, Distance: math.Sqrt(sqDistance)})
+	}
+	s.cross(a.root, b.root)
+
+	sort.Slice(pairs, func(i, j int) bool {
+		return pairs[i].Distance < pairs[j].Distance
+	})
+	return pairs
+}
+
+func joinable(a, b *KDTree) bool {
+	return a != nil && b != nil && a.root != nil && b.root != nil && a.root.Dimensions() == b.root.Dimensions()
+}
+
+// visitNodes calls fn for all nodes of the subtree.
+func (n *node) visitNodes(fn func(*node)) {
+	fn(n)
+	if n.Left != nil {
+		n.Left.visitNodes(fn)
+	}
+	if n.Right != nil {
+		n.Right.visitNodes(fn)
+	}
+}
+
+// knnJoin holds the state of a dual-tree k-nearest neighbour join.
+type knnJoin struct {
+	k int
+	// nearest contains the k-nearest neighbours found so far for every node of the first tree.
+	nearest map[*node]*pq.PriorityQueue
+	// bounds contains the largest k-th distance of all nodes in a subtree of the first tree.
+	bounds map[*node]float64
+}
+
+// join searches the neighbours in the subtree b for all nodes of the subtree a.
+func (j *knnJoin) join(a, b *node) {
+	if math.Sqrt(boxSquaredDistance(a.Bounds, b.Bounds)) > j.bounds[a] {
+		return
+	}
+
+	// split the larger subtree
+	if a.Size >= b.Size {
+		j.point(a, b)
+		first, second := nearerChild(a, b)
+		if first != nil {
+			j.join(first, b)
+		}
+		if second != nil {
+			j.join(second, b)
+		}
+	} else {
+		j.reverse(a, b)
+		first, second := nearerChild(b, a)
+		if first != nil {
+			j.join(a, first)
+		}
+		if second != nil {
+			j.join(a, second)
+		}
+	}
+	j.updateBound(a)
+}
+
+// point searches the neighbours of the single node p in the subtree n.
+func (j *knnJoin) point(p, n *node) {
+	nearestPQ := j.nearest[p]
+	if math.Sqrt(minSquaredDistance(p, n.Bounds)) > getKthOrLastDistance(nearestPQ, j.k-1) {
+		return
+	}
+
+	if d := distance(p, n); d < getKthOrLastDistance(nearestPQ, j.k-1) {
+		nearestPQ.Insert(n, d)
+	}
+	first, second := n.Left, n.Right
+	if first == nil || (second != nil && minSquaredDistance(p, second.Bounds) < minSquaredDistance(p, first.Bounds)) {
+		first, second = second, first
+	}
+	if first != nil {
+		j.point(p, first)
+	}
+	if second != nil {
+		j.point(p, second)
+	}
+}
+
+// reverse offers the single node o as neighbour to all nodes of the subtree n.
+func (j *knnJoin) reverse(n, o *node) {
+	if math.Sqrt(minSquaredDistance(o, n.Bounds)) > j.bounds[n] {
+		return
+	}
+
+	nearestPQ := j.nearest[n]
+	if d := distance(n, o); d < getKthOrLastDistance(nearestPQ, j.k-1) {
+		nearestPQ.Insert(o, d)
+	}
+	if n.Left != nil {
+		j.reverse(n.Left, o)
+	}
+	if n.Right != nil {
+		j.reverse(n.Right, o)
+	}
+	j.updateBound(n)
+}
+
+func (j *knnJoin) updateBound(n *node) {
+	bound := getKthOrLastDistance(j.nearest[n], j.k-1)
+	if n.Left != nil {
+		bound = math.Max(bound, j.bounds[n.Left])
+	}
+	if n.Right != nil {
+		bound = math.Max(bound, j.bounds[n.Right])
+	}
+	j.bounds[n] = bound
+}
@@ -0,0 +1,131 @@
+/*
+ * Copyright 2020 Dennis Kuhnert
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package kdtree_test
+
+import (
+	"github.com/kyroy/kdtree"
+	. "github.com/kyroy/kdtree/points"
+	"github.com/stretchr/testify/assert"
+	"sort"
+	"testing"
+)
+
+func TestKNNJoin(t *testing.T) {
+	stores := kdtree.New([]kdtree.Point{&Point2D{X: 0, Y: 0}, &Point2D{X: 10, Y: 0}, &Point2D{X: 0, Y: 10}})
+	tests := []struct {
+		name   string
+		a, b   *kdtree.KDTree
+		k      int
+		output []kdtree.Pair
+	}{
+		{name: "nil", a: nil, b: stores, k: 1, output: []kdtree.Pair{}},
+		{name: "empty", a: kdtree.New(nil), b: stores, k: 1, output: []kdtree.Pair{}},
+		{name: "k 0", a: kdtree.New([]kdtree.Point{&Point2D{X: 1, Y: 1}}), b: stores, k: 0, output: []kdtree.Pair{}},
+		{name: "wrong dim", a: kdtree.New([]kdtree.Point{&Point3D{X: 1, Y: 1}}), b: stores, k: 1, output: []kdtree.Pair{}},
+		{
+			name: "k 1",
+			a:    kdtree.New([]kdtree.Point{&Point2D{X: 1, Y: 0}}),
+			b:    stores,
+			k:    1,
+			output: []kdtree.Pair{
+				{A: &Point2D{X: 1, Y: 0}, B: &Point2D{X: 0, Y: 0}, Distance: 1},
+			},
+		},
+		{
+			name: "k 2",
+			a:    kdtree.New([]kdtree.Point{&Point2D{X: 6, Y: 0}}),
+			b:    stores,
+			k:    2,
+			output: []kdtree.Pair{
+				{A: &Point2D{X: 6, Y: 0}, B: &Point2D{X: 10, Y: 0}, Distance: 4},
+				{A: &Point2D{X: 6, Y: 0}, B: &Point2D{X: 0, Y: 0}, Distance: 6},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.output, kdtree.KNNJoin(test.a, test.b, test.k))
+		})
+	}
+}
+
+func TestKNNJoinWithGenerator(t *testing.T) {
+	tests := []struct {
+		name  string
+		sizeA int
+		sizeB int
+		k     int
+	}{
+		{name: "a:100,b:1000,k:1", sizeA: 100, sizeB: 1000, k: 1},
+		{name: "a:1000,b:100,k:3", sizeA: 1000, sizeB: 100, k: 3},
+		{name: "a:1000,b:1000,k:5", sizeA: 1000, sizeB: 1000, k: 5},
+		{name: "a:10,b:3,k:5", sizeA: 10, sizeB: 3, k: 5},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			inputA, inputB := generateTestCaseData(test.sizeA), generateTestCaseData(test.sizeB)
+			pairs := kdtree.KNNJoin(kdtree.New(append([]kdtree.Point(nil), inputA...)), kdtree.New(append([]kdtree.Point(nil), inputB...)), test.k)
+
+			groups := make(map[kdtree.Point][]float64)
+			for _, pair := range pairs {
+				groups[pair.A] = append(groups[pair.A], pair.Distance)
+			}
+			assert.Len(t, groups, len(inputA))
+			for _, p := range inputA {
+				var expected []float64
+				for _, o := range prioQueueKNN(inputB, p, test.k) {
+					expected = append(expected, distance(p, o))
+				}
+				assert.Equal(t, expected, groups[p])
+			}
+		})
+	}
+}
+
+func TestDistanceJoin(t *testing.T) {
+	a := kdtree.New([]kdtree.Point{&Point2D{X: 0, Y: 0}, &Point2D{X: 5, Y: 5}})
+	b := kdtree.New([]kdtree.Point{&Point2D{X: 1, Y: 0}, &Point2D{X: 5, Y: 7}, &Point2D{X: 20, Y: 20}})
+	assert.Equal(t, []kdtree.Pair{}, kdtree.DistanceJoin(a, b, -1))
+	assert.Equal(t, []kdtree.Pair{}, kdtree.DistanceJoin(a, nil, 1))
+	assert.Equal(t, []kdtree.Pair{
+		{A: &Point2D{X: 0, Y: 0}, B: &Point2D{X: 1, Y: 0}, Distance: 1},
+		{A: &Point2D{X: 5, Y: 5}, B: &Point2D{X: 5, Y: 7}, Distance: 2},
+	}, kdtree.DistanceJoin(a, b, 2))
+}
+
+func TestDistanceJoinWithGenerator(t *testing.T) {
+	for _, d := range []float64{10, 50, 100} {
+		inputA, inputB := generateTestCaseData(1000), generateTestCaseData(2000)
+		pairs := kdtree.DistanceJoin(kdtree.New(append([]kdtree.Point(nil), inputA...)), kdtree.New(append([]kdtree.Point(nil), inputB...)), d)
+
+		var expected []float64
+		for _, p := range inputA {
+			for _, o := range inputB {
+				if dist := distance(p, o); dist <= d {
+					expected = append(expected, dist)
+				}
+			}
+		}
+		sort.Float64s(expected)
+		actual := make([]float64, 0, len(pairs))
+		for _, pair := range pairs {
+			assert.InDelta(t, distance(pair.A, pair.B), pair.Distance, 1e-9)
+			actual = append(actual, pair.Distance)
+		}
+		assert.InDeltaSlice(t, expected, actual, 1e-9)
+	}
+}
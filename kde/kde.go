@@ -0,0 +1,206 @@
+/*
+ * Copyright 2020 Dennis Kuhnert
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+// Package kde implements kernel density estimation on top of the k-d tree.
+//
+// Subtrees outside of the cutoff radius are skipped. With an error bound, subtrees whose kernel
+// values vary little are approximated as a whole using their bounding box.
+package kde
+
+import (
+	"github.com/kyroy/kdtree"
+	"github.com/kyroy/kdtree/kdrange"
+	"math"
+)
+
+// Kernel is a radial kernel function.
+type Kernel struct {
+	// value returns the unnormalized kernel value for the squared distance divided by the squared bandwidth.
+	value func(u2 float64) float64
+	// support is the radius in bandwidths outside of which the kernel is zero.
+	support float64
+	// norm returns the factor that normalizes the kernel to an integral of 1 in the given dimensions.
+	norm func(dims int) float64
+}
+
+// Gaussian is the kernel exp(-u²/2), normalized to the density of the standard normal distribution.
+var Gaussian = Kernel{
+	value: func(u2 float64) float64 {
+		return math.Exp(-u2 / 2)
+	},
+	support: math.Inf(1),
+	norm: func(dims int) float64 {
+		return math.Pow(2*math.Pi, -float64(dims)/2)
+	},
+}
+
+// Epanechnikov is the kernel 1-u² for u < 1, normalized to an integral of 1.
+var Epanechnikov = Kernel{
+	value: func(u2 float64) float64 {
+		if u2 >= 1 {
+			return 0
+		}
+		return 1 - u2
+	},
+	support: 1,
+	norm: func(dims int) float64 {
+		// (d+2) / (2 * volume of the d-dimensional unit ball)
+		d := float64(dims)
+		volume := math.Pow(math.Pi, d/2) / math.Gamma(d/2+1)
+		return (d + 2) / (2 * volume)
+	},
+}
+
+// Option configures an Estimator.
+type Option func(*Estimator)
+
+// WithCutoff ignores all points farther than radius from the query point.
+// The default is the support of the kernel, which is unbounded for the Gaussian kernel.
+func WithCutoff(radius float64) Option {
+	return func(e *Estimator) {
+		e.sqCutoff = radius * radius
+	}
+}
+
+// WithErrorBound allows an absolute error of at most bound in the returned densities.
+// Subtrees are then approximated as a whole, if the kernel values of their bounding box differ little enough.
+// The default is 0, i.e. the exact density.
+func WithErrorBound(bound float64) Option {
+	return func(e *Estimator) {
+		e.errorBound = bound
+	}
+}
+
+// Estimator estimates the density of the points of a tree.
+type Estimator struct {
+	tree       *kdtree.KDTree
+	kernel     Kernel
+	bandwidth  float64
+	sqCutoff   float64
+	errorBound float64
+}
+
+// New returns an Estimator for the points of the tree with the kernel scaled by the bandwidth.
+//
+// Returns nil if the bandwidth is not positive or the kernel is not one of the kernels of this package.
+func New(tree *kdtree.KDTree, kernel Kernel, bandwidth float64, options ...Option) *Estimator {
+	if bandwidth <= 0 || kernel.value == nil || kernel.norm == nil {
+		return nil
+	}
+	e := &Estimator{
+		tree:      tree,
+		kernel:    kernel,
+		bandwidth: bandwidth,
+		sqCutoff:  kernel.support * kernel.support * bandwidth * bandwidth,
+	}
+	for _, option := range options {
+		option(e)
+	}
+	return e
+}
+
+// Density returns the estimated density at p.
+// It is the average of the kernel centered at all points of the tree.
+//
+// Returns 0 for an empty tree, or when p is nil or p.Dimensions() does not equal the dimensions of the tree's points.
+func (e *Estimator) Density(p kdtree.Point) float64 {
+	root := e.tree.Root()
+	if root.IsEmpty() || p == nil || p.Dimensions() != root.Point().Dimensions() {
+		return 0
+	}
+	norm := e.kernel.norm(p.Dimensions()) / math.Pow(e.bandwidth, float64(p.Dimensions()))
+	s := &sum{Estimator: e, p: p, maxDifference: 2 * e.errorBound / norm}
+	return norm * s.cell(root) / float64(root.Len())
+}
+
+// Densities returns the estimated densities at all points of ps.
+func (e *Estimator) Densities(ps []kdtree.Point) []float64 {
+	result := make([]float64, len(ps))
+	for i, p := range ps {
+		result[i] = e.Density(p)
+	}
+	return result
+}
+
+// sum holds the state of the summation of the kernel values for a single query point.
+type sum struct {
+	*Estimator
+	p kdtree.Point
+	// maxDifference is the largest difference of the unnormalized kernel values within an approximated subtree.
+	maxDifference float64
+}
+
+// cell returns the sum of the unnormalized kernel values of all points in the cell.
+func (s *sum) cell(c kdtree.Cell) float64 {
+	if c.IsEmpty() {
+		return 0
+	}
+	bounds := c.Bounds()
+	minDistance := minSquaredDistance(s.p, bounds)
+	if minDistance > s.sqCutoff {
+		return 0
+	}
+	if maxDistance := maxSquaredDistance(s.p, bounds); s.maxDifference > 0 && maxDistance <= s.sqCutoff {
+		// the error of every point is at most half of the difference
+		high, low := s.value(minDistance), s.value(maxDistance)
+		if high-low <= s.maxDifference {
+			return float64(c.Len()) * (high + low) / 2
+		}
+	}
+
+	result := 0.
+	if d := squaredDistance(s.p, c.Point()); d <= s.sqCutoff {
+		result += s.value(d)
+	}
+	return result + s.cell(c.Left()) + s.cell(c.Right())
+}
+
+func (s *sum) value(sqDistance float64) float64 {
+	return s.kernel.value(sqDistance / (s.bandwidth * s.bandwidth))
+}
+
+func squaredDistance(p1, p2 kdtree.Point) float64 {
+	sum := 0.
+	for i := 0; i < p1.Dimensions(); i++ {
+		d := p1.Dimension(i) - p2.Dimension(i)
+		sum += d * d
+	}
+	return sum
+}
+
+// minSquaredDistance returns the squared distance from p to the nearest point of the box b.
+func minSquaredDistance(p kdtree.Point, b kdrange.Range) float64 {
+	sum := 0.
+	for dim, limit := range b {
+		if v := p.Dimension(dim); v < limit[0] {
+			sum += (limit[0] - v) * (limit[0] - v)
+		} else if v > limit[1] {
+			sum += (v - limit[1]) * (v - limit[1])
+		}
+	}
+	return sum
+}
+
+// maxSquaredDistance returns the squared distance from p to the farthest point of the box b.
+func maxSquaredDistance(p kdtree.Point, b kdrange.Range) float64 {
+	sum := 0.
+	for dim, limit := range b {
+		v := p.Dimension(dim)
+		d := math.Max(v-limit[0], limit[1]-v)
+		sum += d * d
+	}
+	return sum
+}
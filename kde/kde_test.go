@@ -0,0 +1,187 @@
+/*
+ * Copyright 2020 Dennis Kuhnert
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package kde_test
+
+import (
+	"fmt"
+	"github.com/kyroy/kdtree"
+	"github.com/kyroy/kdtree/kde"
+	. "github.com/kyroy/kdtree/points"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	tree := kdtree.New([]kdtree.Point{&Point2D{}})
+	assert.Nil(t, kde.New(tree, kde.Gaussian, 0))
+	assert.Nil(t, kde.New(tree, kde.Gaussian, -1))
+	assert.Nil(t, kde.New(tree, kde.Kernel{}, 1))
+	assert.NotNil(t, kde.New(tree, kde.Gaussian, 1))
+}
+
+func TestEstimator_Density(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     []kdtree.Point
+		kernel    kde.Kernel
+		bandwidth float64
+		options   []kde.Option
+		p         kdtree.Point
+		output    float64
+	}{
+		{name: "empty", input: nil, kernel: kde.Gaussian, bandwidth: 1, p: &Point2D{}, output: 0},
+		{name: "nil point", input: []kdtree.Point{&Point2D{}}, kernel: kde.Gaussian, bandwidth: 1, p: nil, output: 0},
+		{name: "wrong dim", input: []kdtree.Point{&Point2D{}}, kernel: kde.Gaussian, bandwidth: 1, p: &Point3D{}, output: 0},
+		{
+			name:      "gaussian 1d",
+			input:     []kdtree.Point{NewPoint([]float64{0}, nil)},
+			kernel:    kde.Gaussian,
+			bandwidth: 1,
+			p:         NewPoint([]float64{0}, nil),
+			output:    1 / math.Sqrt(2*math.Pi),
+		},
+		{
+			name:      "gaussian 2d bandwidth",
+			input:     []kdtree.Point{&Point2D{X: 0, Y: 0}, &Point2D{X: 3, Y: 4}},
+			kernel:    kde.Gaussian,
+			bandwidth: 2,
+			p:         &Point2D{X: 0, Y: 0},
+			output:    (1 + math.Exp(-25./8)) / (2 * 2 * math.Pi * 4),
+		},
+		{
+			name:      "gaussian cutoff",
+			input:     []kdtree.Point{&Point2D{X: 0, Y: 0}, &Point2D{X: 3, Y: 4}},
+			kernel:    kde.Gaussian,
+			bandwidth: 2,
+			options:   []kde.Option{kde.WithCutoff(4)},
+			p:         &Point2D{X: 0, Y: 0},
+			output:    1 / (2 * 2 * math.Pi * 4),
+		},
+		{
+			name:      "epanechnikov 1d",
+			input:     []kdtree.Point{NewPoint([]float64{0}, nil), NewPoint([]float64{1}, nil), NewPoint([]float64{5}, nil)},
+			kernel:    kde.Epanechnikov,
+			bandwidth: 2,
+			p:         NewPoint([]float64{0}, nil),
+			output:    0.75 * (1 + 0.75) / 2 / 3,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e := kde.New(kdtree.New(test.input), test.kernel, test.bandwidth, test.options...)
+			assert.InDelta(t, test.output, e.Density(test.p), 1e-12)
+		})
+	}
+}
+
+func TestKernelNormalization(t *testing.T) {
+	tests := []struct {
+		name   string
+		kernel kde.Kernel
+	}{
+		{name: "gaussian", kernel: kde.Gaussian},
+		{name: "epanechnikov", kernel: kde.Epanechnikov},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e := kde.New(kdtree.New([]kdtree.Point{&Point2D{X: 0, Y: 0}}), test.kernel, 0.5)
+			// integrate the density on a grid
+			step, integral := 0.01, 0.
+			for x := -5.; x < 5; x += step {
+				for y := -5.; y < 5; y += step {
+					integral += e.Density(&Point2D{X: x, Y: y}) * step * step
+				}
+			}
+			assert.InDelta(t, 1, integral, 1e-3)
+		})
+	}
+}
+
+func TestEstimator_DensityWithGenerator(t *testing.T) {
+	input := generateTestCaseData(2000)
+	tree := kdtree.New(append([]kdtree.Point(nil), input...))
+	queries := generateTestCaseData(50)
+
+	tests := []struct {
+		name      string
+		kernel    kde.Kernel
+		norm      float64
+		value     func(u float64) float64
+		bandwidth float64
+		cutoff    float64
+	}{
+		{name: "gaussian", kernel: kde.Gaussian, norm: 1 / (2 * math.Pi), value: gaussian, bandwidth: 100, cutoff: math.Inf(1)},
+		{name: "gaussian cutoff", kernel: kde.Gaussian, norm: 1 / (2 * math.Pi), value: gaussian, bandwidth: 100, cutoff: 250},
+		{name: "epanechnikov", kernel: kde.Epanechnikov, norm: 2 / math.Pi, value: epanechnikov, bandwidth: 200, cutoff: 200},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			exact := kde.New(tree, test.kernel, test.bandwidth, kde.WithCutoff(test.cutoff))
+			for _, q := range queries {
+				assert.InDelta(t, bruteForceDensity(input, q, test.norm, test.value, test.bandwidth, test.cutoff), exact.Density(q), 1e-15)
+			}
+
+			bound := 1e-8
+			approximate := kde.New(tree, test.kernel, test.bandwidth, kde.WithCutoff(test.cutoff), kde.WithErrorBound(bound))
+			for i, d := range approximate.Densities(queries) {
+				assert.InDelta(t, exact.Density(queries[i]), d, bound)
+			}
+		})
+	}
+}
+
+func BenchmarkEstimator_Density(b *testing.B) {
+	tree := kdtree.New(generateTestCaseData(100000))
+	queries := generateTestCaseData(100)
+	for _, bound := range []float64{0, 1e-9} {
+		e := kde.New(tree, kde.Gaussian, 50, kde.WithErrorBound(bound))
+		b.Run(fmt.Sprintf("bound:%g", bound), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				e.Densities(queries)
+			}
+		})
+	}
+}
+
+func gaussian(u float64) float64 {
+	return math.Exp(-u * u / 2)
+}
+
+func epanechnikov(u float64) float64 {
+	return math.Max(0, 1-u*u)
+}
+
+// bruteForceDensity returns the density at q of the normalized kernel value for all 2D points within the cutoff.
+func bruteForceDensity(input []kdtree.Point, q kdtree.Point, norm float64, value func(u float64) float64, bandwidth, cutoff float64) float64 {
+	sum := 0.
+	for _, p := range input {
+		if d := math.Hypot(p.Dimension(0)-q.Dimension(0), p.Dimension(1)-q.Dimension(1)); d <= cutoff {
+			sum += value(d / bandwidth)
+		}
+	}
+	return norm * sum / (bandwidth * bandwidth * float64(len(input)))
+}
+
+func generateTestCaseData(size int) []kdtree.Point {
+	var points []kdtree.Point
+	for i := 0; i < size; i++ {
+		points = append(points, &Point2D{X: rand.Float64()*3000 - 1500, Y: rand.Float64()*3000 - 1500})
+	}
+	return points
+}
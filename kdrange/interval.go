@@ -0,0 +1,275 @@
+/*
+ * Copyright 2020 Dennis Kuhnert
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package kdrange
+
+import (
+	"fmt"
+	"math"
+)
+
+// Bound is the lower or upper limit of an Interval.
+type Bound struct {
+	// Value is the limit of the interval.
+	Value float64
+	// Open excludes Value itself from the interval.
+	Open bool
+	// Unbounded marks a side without any limit. Value and Open are ignored.
+	Unbounded bool
+}
+
+// Interval represents a range in one dimension.
+// Each side can be inclusive, exclusive or unbounded.
+type Interval struct {
+	Min Bound
+	Max Bound
+}
+
+// Closed returns the interval min <= x <= max.
+func Closed(min, max float64) Interval {
+	return Interval{Min: Bound{Value: min}, Max: Bound{Value: max}}
+}
+
+// Open returns the interval min < x < max.
+func Open(min, max float64) Interval {
+	return Interval{Min: Bound{Value: min, Open: true}, Max: Bound{Value: max, Open: true}}
+}
+
+// ClosedOpen returns the interval min <= x < max.
+func ClosedOpen(min, max float64) Interval {
+	return Interval{Min: Bound{Value: min}, Max: Bound{Value: max, Open: true}}
+}
+
+// OpenClosed returns the interval min < x <= max.
+func OpenClosed(min, max float64) Interval {
+	return Interval{Min: Bound{Value: min, Open: true}, Max: Bound{Value: max}}
+}
+
+// AtLeast returns the interval x >= min.
+func AtLeast(min float64) Interval {
+	return Interval{Min: Bound{Value: min}, Max: Bound{Unbounded: true}}
+}
+
+// GreaterThan returns the interval x > min.
+func GreaterThan(min float64) Interval {
+	return Interval{Min: Bound{Value: min, Open: true}, Max: Bound{Unbounded: true}}
+}
+
+// AtMost returns the interval x <= max.
+func AtMost(max float64) Interval {
+	return Interval{Min: Bound{Unbounded: true}, Max: Bound{Value: max}}
+}
+
+// LessThan returns the interval x < max.
+func LessThan(max float64) Interval {
+	return Interval{Min: Bound{Unbounded: true}, Max: Bound{Value: max, Open: true}}
+}
+
+// Unbounded returns the interval containing all values.
+func Unbounded() Interval {
+	return Interval{Min: Bound{Unbounded: true}, Max: Bound{Unbounded: true}}
+}
+
+// AboveMin reports whether v satisfies the lower bound of the interval.
+func (i Interval) AboveMin(v float64) bool {
+	if i.Min.Unbounded {
+		return true
+	}
+	if i.Min.Open {
+		return v > i.Min.Value
+	}
+	return v >= i.Min.Value
+}
+
+// BelowMax reports whether v satisfies the upper bound of the interval.
+func (i Interval) BelowMax(v float64) bool {
+	if i.Max.Unbounded {
+		return true
+	}
+	if i.Max.Open {
+		return v < i.Max.Value
+	}
+	return v <= i.Max.Value
+}
+
+// Contains reports whether v lies inside the interval.
+func (i Interval) Contains(v float64) bool {
+	return i.AboveMin(v) && i.BelowMax(v)
+}
+
+// String returns the interval in mathematical notation, e.g. "[1, 3)".
+func (i Interval) String() string {
+	lower, upper := "[", "]"
+	min, max := i.Min.Value, i.Max.Value
+	if i.Min.Unbounded {
+		lower, min = "(", math.Inf(-1)
+	} else if i.Min.Open {
+		lower = "("
+	}
+	if i.Max.Unbounded {
+		upper, max = ")", math.Inf(1)
+	} else if i.Max.Open {
+		upper = ")"
+	}
+	return fmt.Sprintf("%s%v, %v%s", lower, min, max, upper)
+}
+
+// Box represents a range in k-dimensional space made of one Interval per dimension.
+//
+// For example all points with x > 3 and 0 <= y < 1:
+//
+//     b := NewBox(GreaterThan(3), ClosedOpen(0, 1))
+type Box []Interval
+
+// NewBox creates a new Box from the given intervals.
+func NewBox(intervals ...Interval) Box {
+	return intervals
+}
+
+// Intervals returns the limits of every dimension.
+// Returns nil if len(b) does not equal dims.
+func (b Box) Intervals(dims int) []Interval {
+	if len(b) != dims {
+		return nil
+	}
+	return b
+}
+
+// Contains reports whether the point p lies inside the box.
+func (b Box) Contains(p Point) bool {
+	return p != nil && intervalsContain(b.Intervals(p.Dimensions()), p)
+}
+
+// Intersects reports whether the box overlaps with the range o.
+func (b Box) Intersects(o Range) bool {
+	return intervalsIntersect(b.Intervals(len(o)), o)
+}
+
+// ContainsRange reports whether the range o lies completely inside the box.
+func (b Box) ContainsRange(o Range) bool {
+	return intervalsContainRange(b.Intervals(len(o)), o)
+}
+
+// Partial represents a range in k-dimensional space that only constrains some dimensions.
+// It maps the index of a dimension to its interval, all other dimensions are unbounded.
+// An empty Partial contains every point.
+//
+// For example all points with a time (dim 3) between t0 and t1, regardless of their other coordinates:
+//
+//     p := Partial{3: ClosedOpen(t0, t1)}
+type Partial map[int]Interval
+
+// Intervals returns the limits of every dimension. Dimensions without an interval are unbounded.
+// Returns nil if the Partial constrains a dimension outside of [0, dims).
+func (p Partial) Intervals(dims int) []Interval {
+	if !p.fits(dims) {
+		return nil
+	}
+	intervals := make([]Interval, dims)
+	for i := range intervals {
+		intervals[i] = Unbounded()
+	}
+	for dim, interval := range p {
+		intervals[dim] = interval
+	}
+	return intervals
+}
+
+// Contains reports whether the point p lies inside the partial range.
+func (p Partial) Contains(point Point) bool {
+	if point == nil || !p.fits(point.Dimensions()) {
+		return false
+	}
+	for dim, interval := range p {
+		if !interval.Contains(point.Dimension(dim)) {
+			return false
+		}
+	}
+	return true
+}
+
+// Intersects reports whether the partial range overlaps with the range o.
+func (p Partial) Intersects(o Range) bool {
+	if !p.fits(len(o)) {
+		return false
+	}
+	for dim, interval := range p {
+		if !interval.AboveMin(o[dim][1]) || !interval.BelowMax(o[dim][0]) {
+			return false
+		}
+	}
+	return true
+}
+
+// ContainsRange reports whether the range o lies completely inside the partial range.
+func (p Partial) ContainsRange(o Range) bool {
+	if !p.fits(len(o)) {
+		return false
+	}
+	for dim, interval := range p {
+		if !interval.AboveMin(o[dim][0]) || !interval.BelowMax(o[dim][1]) {
+			return false
+		}
+	}
+	return true
+}
+
+// fits reports whether all constrained dimensions lie in [0, dims).
+// The methods check the map directly, so queries do not allocate for every visited node.
+func (p Partial) fits(dims int) bool {
+	for dim := range p {
+		if dim < 0 || dim >= dims {
+			return false
+		}
+	}
+	return true
+}
+
+func intervalsContain(intervals []Interval, p Point) bool {
+	if intervals == nil {
+		return false
+	}
+	for dim, interval := range intervals {
+		if !interval.Contains(p.Dimension(dim)) {
+			return false
+		}
+	}
+	return true
+}
+
+func intervalsIntersect(intervals []Interval, r Range) bool {
+	if intervals == nil {
+		return false
+	}
+	for dim, interval := range intervals {
+		if !interval.AboveMin(r[dim][1]) || !interval.BelowMax(r[dim][0]) {
+			return false
+		}
+	}
+	return true
+}
+
+func intervalsContainRange(intervals []Interval, r Range) bool {
+	if intervals == nil {
+		return false
+	}
+	for dim, interval := range intervals {
+		if !interval.AboveMin(r[dim][0]) || !interval.BelowMax(r[dim][1]) {
+			return false
+		}
+	}
+	return true
+}
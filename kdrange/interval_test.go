@@ -0,0 +1,97 @@
+/*
+ * Copyright 2020 Dennis Kuhnert
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package kdrange_test
+
+import (
+	"github.com/kyroy/kdtree/kdrange"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestInterval_Contains(t *testing.T) {
+	tests := []struct {
+		name     string
+		interval kdrange.Interval
+		inside   []float64
+		outside  []float64
+	}{
+		{name: "closed", interval: kdrange.Closed(1, 3), inside: []float64{1, 2, 3}, outside: []float64{0.9, 3.1}},
+		{name: "open", interval: kdrange.Open(1, 3), inside: []float64{1.1, 2, 2.9}, outside: []float64{1, 3}},
+		{name: "closed open", interval: kdrange.ClosedOpen(1, 3), inside: []float64{1, 2}, outside: []float64{0.9, 3}},
+		{name: "open closed", interval: kdrange.OpenClosed(1, 3), inside: []float64{2, 3}, outside: []float64{1, 3.1}},
+		{name: "at least", interval: kdrange.AtLeast(1), inside: []float64{1, 1e300}, outside: []float64{0.9}},
+		{name: "greater than", interval: kdrange.GreaterThan(1), inside: []float64{1.1, 1e300}, outside: []float64{1}},
+		{name: "at most", interval: kdrange.AtMost(1), inside: []float64{-1e300, 1}, outside: []float64{1.1}},
+		{name: "less than", interval: kdrange.LessThan(1), inside: []float64{-1e300, 0.9}, outside: []float64{1}},
+		{name: "unbounded", interval: kdrange.Unbounded(), inside: []float64{-1e300, 0, 1e300}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for _, v := range test.inside {
+				assert.True(t, test.interval.Contains(v), "%v should contain %v", test.interval, v)
+			}
+			for _, v := range test.outside {
+				assert.False(t, test.interval.Contains(v), "%v should not contain %v", test.interval, v)
+			}
+		})
+	}
+}
+
+func TestInterval_String(t *testing.T) {
+	tests := []struct {
+		name     string
+		interval kdrange.Interval
+		expected string
+	}{
+		{name: "closed", interval: kdrange.Closed(1, 3), expected: "[1, 3]"},
+		{name: "open", interval: kdrange.Open(1, 3), expected: "(1, 3)"},
+		{name: "closed open", interval: kdrange.ClosedOpen(1, 3.5), expected: "[1, 3.5)"},
+		{name: "greater than", interval: kdrange.GreaterThan(1), expected: "(1, +Inf)"},
+		{name: "at most", interval: kdrange.AtMost(1), expected: "(-Inf, 1]"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.interval.String())
+		})
+	}
+}
+
+func TestNewBox(t *testing.T) {
+	b := kdrange.NewBox(kdrange.GreaterThan(3), kdrange.ClosedOpen(0, 1))
+	assert.Equal(t, []kdrange.Interval{kdrange.GreaterThan(3), kdrange.ClosedOpen(0, 1)}, b.Intervals(2))
+	assert.Nil(t, b.Intervals(3))
+}
+
+func TestPartial_Intervals(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  kdrange.Partial
+		dims   int
+		output []kdrange.Interval
+	}{
+		{name: "nil", input: nil, dims: 2, output: []kdrange.Interval{kdrange.Unbounded(), kdrange.Unbounded()}},
+		{name: "1 of 3", input: kdrange.Partial{1: kdrange.Closed(1, 2)}, dims: 3, output: []kdrange.Interval{kdrange.Unbounded(), kdrange.Closed(1, 2), kdrange.Unbounded()}},
+		{name: "2 of 3", input: kdrange.Partial{0: kdrange.AtMost(4), 2: kdrange.Closed(1, 2)}, dims: 3, output: []kdrange.Interval{kdrange.AtMost(4), kdrange.Unbounded(), kdrange.Closed(1, 2)}},
+		{name: "out of bounds", input: kdrange.Partial{3: kdrange.Closed(1, 2)}, dims: 3, output: nil},
+		{name: "negative", input: kdrange.Partial{-1: kdrange.Closed(1, 2)}, dims: 3, output: nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.output, test.input.Intervals(test.dims))
+		})
+	}
+}
@@ -0,0 +1,162 @@
+/*
+ * Copyright 2020 Dennis Kuhnert
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package kdrange
+
+import "math"
+
+// Polygon represents a simple 2-dimensional polygon, which may be non-convex.
+// Points on the edges are inside the polygon.
+// A nil Polygon or one with less than 3 vertices contains no points.
+type Polygon struct {
+	Vertices [][2]float64
+}
+
+// NewPolygon creates a new Polygon from its vertices in order. The polygon is closed implicitly.
+//
+// Returns nil if less than 3 vertices are given.
+func NewPolygon(vertices ...[2]float64) *Polygon {
+	if len(vertices) < 3 {
+		return nil
+	}
+	return &Polygon{Vertices: vertices}
+}
+
+// Contains reports whether the point p lies inside the polygon.
+func (p *Polygon) Contains(point Point) bool {
+	if !p.valid() || point == nil || point.Dimensions() != 2 {
+		return false
+	}
+	return p.contains(point.Dimension(0), point.Dimension(1))
+}
+
+// Intersects reports whether the polygon overlaps with the box r.
+func (p *Polygon) Intersects(r Range) bool {
+	if !p.valid() || len(r) != 2 {
+		return false
+	}
+	bounds := p.bounds()
+	if !bounds.Intersects(r) {
+		return false
+	}
+	if r.ContainsRange(bounds) {
+		return true
+	}
+	// a corner of r inside of p or an edge of p crossing r
+	if p.contains(r[0][0], r[1][0]) {
+		return true
+	}
+	for i := range p.Vertices {
+		if segmentIntersectsRange(p.edge(i), r) {
+			return true
+		}
+	}
+	return false
+}
+
+// ContainsRange reports whether the box r lies completely inside the polygon.
+// Boxes touching the edges of the polygon are not reported as inside.
+func (p *Polygon) ContainsRange(r Range) bool {
+	if !p.valid() || len(r) != 2 || !p.bounds().ContainsRange(r) {
+		return false
+	}
+	// with all corners inside, only an edge of p crossing r can cut off parts of it
+	for _, x := range r[0] {
+		for _, y := range r[1] {
+			if !p.contains(x, y) {
+				return false
+			}
+		}
+	}
+	for i := range p.Vertices {
+		if segmentIntersectsRange(p.edge(i), r) {
+			return false
+		}
+	}
+	return true
+}
+
+// valid reports whether the polygon is not nil and has at least 3 vertices.
+func (p *Polygon) valid() bool {
+	return p != nil && len(p.Vertices) >= 3
+}
+
+// bounds returns the bounding box of the vertices.
+func (p *Polygon) bounds() Range {
+	bounds := Range{{p.Vertices[0][0], p.Vertices[0][0]}, {p.Vertices[0][1], p.Vertices[0][1]}}
+	for _, v := range p.Vertices[1:] {
+		for dim := range bounds {
+			bounds[dim][0] = math.Min(bounds[dim][0], v[dim])
+			bounds[dim][1] = math.Max(bounds[dim][1], v[dim])
+		}
+	}
+	return bounds
+}
+
+// edge returns the edge from the i-th to the next vertex.
+func (p *Polygon) edge(i int) [2][2]float64 {
+	return [2][2]float64{p.Vertices[i], p.Vertices[(i+1)%len(p.Vertices)]}
+}
+
+// contains implements the even-odd rule with points on the edges being inside.
+func (p *Polygon) contains(x, y float64) bool {
+	inside := false
+	for i := range p.Vertices {
+		e := p.edge(i)
+		a, b := e[0], e[1]
+		if onSegment(x, y, a, b) {
+			return true
+		}
+		if (a[1] > y) != (b[1] > y) && x < a[0]+(y-a[1])*(b[0]-a[0])/(b[1]-a[1]) {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// onSegment reports whether (x, y) lies on the segment from a to b.
+func onSegment(x, y float64, a, b [2]float64) bool {
+	cross := (b[0]-a[0])*(y-a[1]) - (b[1]-a[1])*(x-a[0])
+	if cross != 0 {
+		return false
+	}
+	return math.Min(a[0], b[0]) <= x && x <= math.Max(a[0], b[0]) &&
+		math.Min(a[1], b[1]) <= y && y <= math.Max(a[1], b[1])
+}
+
+// segmentIntersectsRange reports whether the segment s has a common point with the 2-dimensional box r.
+// It clips the segment against every side of the box (Liang-Barsky).
+func segmentIntersectsRange(s [2][2]float64, r Range) bool {
+	t0, t1 := 0., 1.
+	for dim := 0; dim < 2; dim++ {
+		d := s[1][dim] - s[0][dim]
+		if d == 0 {
+			if s[0][dim] < r[dim][0] || s[0][dim] > r[dim][1] {
+				return false
+			}
+			continue
+		}
+		ta, tb := (r[dim][0]-s[0][dim])/d, (r[dim][1]-s[0][dim])/d
+		if ta > tb {
+			ta, tb = tb, ta
+		}
+		t0, t1 = math.Max(t0, ta), math.Min(t1, tb)
+		if t0 > t1 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,112 @@
+/*
+ * Copyright 2020 Dennis Kuhnert
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package kdrange_test
+
+import (
+	"github.com/kyroy/kdtree/kdrange"
+	"github.com/kyroy/kdtree/points"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// lShape is the non-convex polygon
+//
+//     4 +---+
+//       |   |
+//     1 |   +-------+
+//       |           |
+//     0 +-----------+
+//       0   1       4
+var lShape = kdrange.NewPolygon([2]float64{0, 0}, [2]float64{4, 0}, [2]float64{4, 1}, [2]float64{1, 1}, [2]float64{1, 4}, [2]float64{0, 4})
+
+func TestNewPolygon(t *testing.T) {
+	assert.Nil(t, kdrange.NewPolygon())
+	assert.Nil(t, kdrange.NewPolygon([2]float64{0, 0}, [2]float64{1, 1}))
+	assert.NotNil(t, kdrange.NewPolygon([2]float64{0, 0}, [2]float64{1, 1}, [2]float64{0, 1}))
+}
+
+func TestPolygon_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		polygon *kdrange.Polygon
+	}{
+		{name: "nil", polygon: nil},
+		{name: "constructor with 2 vertices", polygon: kdrange.NewPolygon([2]float64{-1, -1}, [2]float64{1, 1})},
+		{name: "literal with 2 vertices", polygon: &kdrange.Polygon{Vertices: [][2]float64{{-1, -1}, {1, 1}}}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.False(t, test.polygon.Contains(&points.Point2D{X: 0, Y: 0}))
+			assert.False(t, test.polygon.Intersects(kdrange.New(-2, 2, -2, 2)))
+			assert.False(t, test.polygon.ContainsRange(kdrange.New(-0.1, 0.1, -0.1, 0.1)))
+		})
+	}
+}
+
+func TestPolygon_Literal(t *testing.T) {
+	polygon := &kdrange.Polygon{Vertices: lShape.Vertices}
+	assert.True(t, polygon.Contains(&points.Point2D{X: 3, Y: 0.5}))
+	assert.False(t, polygon.Contains(&points.Point2D{X: 2, Y: 2}))
+	assert.True(t, polygon.Intersects(kdrange.New(2, 3, 0, 2)))
+	assert.True(t, polygon.ContainsRange(kdrange.New(2, 3, 0.2, 0.8)))
+	assert.False(t, polygon.Intersects(kdrange.New(2, 3, 2, 3)))
+}
+
+func TestPolygon_Contains(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    kdrange.Point
+		expected bool
+	}{
+		{name: "nil", input: nil, expected: false},
+		{name: "wrong dim", input: &points.Point3D{X: 0.5, Y: 0.5}, expected: false},
+		{name: "inside", input: &points.Point2D{X: 0.5, Y: 0.5}, expected: true},
+		{name: "inside arm", input: &points.Point2D{X: 3, Y: 0.5}, expected: true},
+		{name: "on edge", input: &points.Point2D{X: 2, Y: 1}, expected: true},
+		{name: "on vertex", input: &points.Point2D{X: 1, Y: 1}, expected: true},
+		{name: "in notch", input: &points.Point2D{X: 2, Y: 2}, expected: false},
+		{name: "outside bounds", input: &points.Point2D{X: 5, Y: 0.5}, expected: false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, lShape.Contains(test.input))
+		})
+	}
+}
+
+func TestPolygon_Intersects(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      kdrange.Range
+		intersects bool
+		contains   bool
+	}{
+		{name: "wrong dim", input: kdrange.New(0, 1), intersects: false, contains: false},
+		{name: "inside", input: kdrange.New(0.2, 0.8, 0.2, 3), intersects: true, contains: true},
+		{name: "in notch", input: kdrange.New(2, 3, 2, 3), intersects: false, contains: false},
+		{name: "covering notch", input: kdrange.New(0.5, 3, 0.5, 3), intersects: true, contains: false},
+		{name: "around polygon", input: kdrange.New(-1, 5, -1, 5), intersects: true, contains: false},
+		{name: "crossing edge", input: kdrange.New(3, 5, 0.5, 0.7), intersects: true, contains: false},
+		{name: "outside", input: kdrange.New(5, 6, 0, 1), intersects: false, contains: false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.intersects, lShape.Intersects(test.input))
+			assert.Equal(t, test.contains, lShape.ContainsRange(test.input))
+		})
+	}
+}
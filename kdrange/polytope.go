@@ -0,0 +1,103 @@
+/*
+ * Copyright 2020 Dennis Kuhnert
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package kdrange
+
+// HalfSpace represents all points x with Normal·x <= Offset.
+type HalfSpace struct {
+	Normal []float64
+	Offset float64
+}
+
+// Polytope represents the intersection of half-spaces, i.e. a convex polytope.
+// It may be unbounded. Points on the boundary are inside the polytope.
+//
+// For example the triangle with the corners (0,0), (1,0) and (0,1):
+//
+//     p := NewPolytope(
+//         HalfSpace{Normal: []float64{-1, 0}, Offset: 0}, // x >= 0
+//         HalfSpace{Normal: []float64{0, -1}, Offset: 0}, // y >= 0
+//         HalfSpace{Normal: []float64{1, 1}, Offset: 1},  // x + y <= 1
+//     )
+type Polytope []HalfSpace
+
+// NewPolytope creates a new Polytope from the given half-spaces.
+func NewPolytope(halfSpaces ...HalfSpace) Polytope {
+	return halfSpaces
+}
+
+// Contains reports whether the point p lies inside the polytope.
+func (p Polytope) Contains(point Point) bool {
+	if point == nil {
+		return false
+	}
+	for _, h := range p {
+		if len(h.Normal) != point.Dimensions() {
+			return false
+		}
+		sum := 0.
+		for dim, n := range h.Normal {
+			sum += n * point.Dimension(dim)
+		}
+		if sum > h.Offset {
+			return false
+		}
+	}
+	return true
+}
+
+// Intersects reports whether the polytope might overlap with the box r.
+// Only boxes that lie completely outside of a single half-space are reported as disjoint.
+func (p Polytope) Intersects(r Range) bool {
+	for _, h := range p {
+		if len(h.Normal) != len(r) {
+			return false
+		}
+		min := 0.
+		for dim, n := range h.Normal {
+			if n > 0 {
+				min += n * r[dim][0]
+			} else {
+				min += n * r[dim][1]
+			}
+		}
+		if min > h.Offset {
+			return false
+		}
+	}
+	return true
+}
+
+// ContainsRange reports whether the box r lies completely inside the polytope.
+func (p Polytope) ContainsRange(r Range) bool {
+	for _, h := range p {
+		if len(h.Normal) != len(r) {
+			return false
+		}
+		max := 0.
+		for dim, n := range h.Normal {
+			if n > 0 {
+				max += n * r[dim][1]
+			} else {
+				max += n * r[dim][0]
+			}
+		}
+		if max > h.Offset {
+			return false
+		}
+	}
+	return true
+}
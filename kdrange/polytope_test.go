@@ -0,0 +1,71 @@
+/*
+ * Copyright 2020 Dennis Kuhnert
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package kdrange_test
+
+import (
+	"github.com/kyroy/kdtree/kdrange"
+	"github.com/kyroy/kdtree/points"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// triangle with the corners (0,0), (1,0) and (0,1)
+var triangle = kdrange.NewPolytope(
+	kdrange.HalfSpace{Normal: []float64{-1, 0}, Offset: 0},
+	kdrange.HalfSpace{Normal: []float64{0, -1}, Offset: 0},
+	kdrange.HalfSpace{Normal: []float64{1, 1}, Offset: 1},
+)
+
+func TestPolytope_Contains(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    kdrange.Point
+		expected bool
+	}{
+		{name: "nil", input: nil, expected: false},
+		{name: "wrong dim", input: &points.Point3D{X: 0.1, Y: 0.1}, expected: false},
+		{name: "inside", input: &points.Point2D{X: 0.2, Y: 0.3}, expected: true},
+		{name: "on boundary", input: &points.Point2D{X: 0.5, Y: 0.5}, expected: true},
+		{name: "outside", input: &points.Point2D{X: 0.6, Y: 0.6}, expected: false},
+		{name: "negative", input: &points.Point2D{X: -0.1, Y: 0.5}, expected: false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, triangle.Contains(test.input))
+		})
+	}
+}
+
+func TestPolytope_Intersects(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      kdrange.Range
+		intersects bool
+		contains   bool
+	}{
+		{name: "wrong dim", input: kdrange.New(0, 1), intersects: false, contains: false},
+		{name: "inside", input: kdrange.New(0, 0.5, 0, 0.5), intersects: true, contains: true},
+		{name: "overlapping", input: kdrange.New(0.4, 2, 0.4, 2), intersects: true, contains: false},
+		{name: "outside", input: kdrange.New(1.1, 2, 0, 2), intersects: false, contains: false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.intersects, triangle.Intersects(test.input))
+			assert.Equal(t, test.contains, triangle.ContainsRange(test.input))
+		})
+	}
+}
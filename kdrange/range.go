@@ -17,6 +17,8 @@
 // Package kdrange contains k-dimensional range struct and helpers.
 package kdrange
 
+import "math"
+
 // Range represents a range in k-dimensional space.
 type Range [][2]float64
 
@@ -40,3 +42,145 @@ func New(limits ...float64) Range {
 	}
 	return r
 }
+
+// Point specifies a point in k-dimensional space.
+// It is satisfied by kdtree.Point.
+type Point interface {
+	// Dimensions returns the total number of dimensions.
+	Dimensions() int
+	// Dimension returns the value of the i-th dimension.
+	Dimension(i int) float64
+}
+
+// BoundingRange returns the smallest Range containing all given points.
+//
+// Returns nil when no points are given or the points differ in their dimensions.
+func BoundingRange(points ...Point) Range {
+	if len(points) == 0 {
+		return nil
+	}
+	r := make(Range, points[0].Dimensions())
+	for i := range r {
+		v := points[0].Dimension(i)
+		r[i] = [2]float64{v, v}
+	}
+	for _, p := range points[1:] {
+		if p.Dimensions() != len(r) {
+			return nil
+		}
+		for i := range r {
+			r[i][0] = math.Min(r[i][0], p.Dimension(i))
+			r[i][1] = math.Max(r[i][1], p.Dimension(i))
+		}
+	}
+	return r
+}
+
+// Contains reports whether the point p lies inside the range.
+func (r Range) Contains(p Point) bool {
+	if p == nil || p.Dimensions() != len(r) {
+		return false
+	}
+	for dim, limit := range r {
+		if limit[0] > p.Dimension(dim) || limit[1] < p.Dimension(dim) {
+			return false
+		}
+	}
+	return true
+}
+
+// ContainsRange reports whether the range o lies completely inside the range.
+func (r Range) ContainsRange(o Range) bool {
+	if len(o) != len(r) {
+		return false
+	}
+	for dim, limit := range r {
+		if limit[0] > o[dim][0] || limit[1] < o[dim][1] {
+			return false
+		}
+	}
+	return true
+}
+
+// Intersects reports whether the range overlaps with the range o.
+// Ranges touching at their limits intersect.
+func (r Range) Intersects(o Range) bool {
+	if len(o) != len(r) {
+		return false
+	}
+	for dim, limit := range r {
+		if limit[0] > o[dim][1] || limit[1] < o[dim][0] {
+			return false
+		}
+	}
+	return true
+}
+
+// Intersection returns the range covered by both the range and the range o.
+//
+// Returns nil when the ranges do not intersect.
+func (r Range) Intersection(o Range) Range {
+	if !r.Intersects(o) {
+		return nil
+	}
+	intersection := make(Range, len(r))
+	for dim, limit := range r {
+		intersection[dim] = [2]float64{math.Max(limit[0], o[dim][0]), math.Min(limit[1], o[dim][1])}
+	}
+	return intersection
+}
+
+// Union returns the smallest range containing both the range and the range o.
+//
+// Returns nil when the ranges differ in their dimensions.
+func (r Range) Union(o Range) Range {
+	if len(o) != len(r) {
+		return nil
+	}
+	union := make(Range, len(r))
+	for dim, limit := range r {
+		union[dim] = [2]float64{math.Min(limit[0], o[dim][0]), math.Max(limit[1], o[dim][1])}
+	}
+	return union
+}
+
+// Expand returns a copy of the range that is extended by the given value in every direction.
+// A negative value shrinks the range; dimensions shrunk below zero width collapse to their center.
+func (r Range) Expand(by float64) Range {
+	if r == nil {
+		return nil
+	}
+	expanded := make(Range, len(r))
+	for dim, limit := range r {
+		min, max := limit[0]-by, limit[1]+by
+		if min > max {
+			min = (limit[0] + limit[1]) / 2
+			max = min
+		}
+		expanded[dim] = [2]float64{min, max}
+	}
+	return expanded
+}
+
+// Volume returns the product of the widths of all dimensions.
+//
+// Returns 0 for a range without dimensions.
+func (r Range) Volume() float64 {
+	if len(r) == 0 {
+		return 0
+	}
+	volume := 1.
+	for _, limit := range r {
+		volume *= math.Max(limit[1]-limit[0], 0)
+	}
+	return volume
+}
+
+// Center returns the coordinates of the center of the range.
+func (r Range) Center() []float64 {
+	center := make([]float64, len(r))
+	for dim, limit := range r {
+		center[dim] = (limit[0] + limit[1]) / 2
+	}
+	return center
+}
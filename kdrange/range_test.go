@@ -18,6 +18,7 @@ package kdrange_test
 
 import (
 	"github.com/kyroy/kdtree/kdrange"
+	"github.com/kyroy/kdtree/points"
 	"github.com/stretchr/testify/assert"
 	"testing"
 )
@@ -60,3 +61,160 @@ func TestNewRange(t *testing.T) {
 		})
 	}
 }
+
+func TestBoundingRange(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  []kdrange.Point
+		output kdrange.Range
+	}{
+		{name: "nil", input: nil, output: nil},
+		{name: "1", input: []kdrange.Point{&points.Point2D{X: 1, Y: 2}}, output: kdrange.New(1, 1, 2, 2)},
+		{name: "3", input: []kdrange.Point{&points.Point2D{X: 1, Y: 2}, &points.Point2D{X: -1, Y: 5}, &points.Point2D{X: 3, Y: 3}}, output: kdrange.New(-1, 3, 2, 5)},
+		{name: "different dimensions", input: []kdrange.Point{&points.Point2D{X: 1, Y: 2}, &points.Point3D{}}, output: nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.output, kdrange.BoundingRange(test.input...))
+		})
+	}
+}
+
+func TestRange_Contains(t *testing.T) {
+	tests := []struct {
+		name     string
+		r        kdrange.Range
+		input    kdrange.Point
+		expected bool
+	}{
+		{name: "nil", r: kdrange.New(0, 1, 0, 1), input: nil, expected: false},
+		{name: "wrong dim", r: kdrange.New(0, 1, 0, 1), input: &points.Point3D{}, expected: false},
+		{name: "inside", r: kdrange.New(0, 1, 0, 1), input: &points.Point2D{X: 0.5, Y: 0.5}, expected: true},
+		{name: "on limit", r: kdrange.New(0, 1, 0, 1), input: &points.Point2D{X: 1, Y: 0}, expected: true},
+		{name: "outside", r: kdrange.New(0, 1, 0, 1), input: &points.Point2D{X: 0.5, Y: 1.5}, expected: false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.r.Contains(test.input))
+		})
+	}
+}
+
+func TestRange_ContainsRange(t *testing.T) {
+	tests := []struct {
+		name     string
+		r        kdrange.Range
+		input    kdrange.Range
+		expected bool
+	}{
+		{name: "wrong dim", r: kdrange.New(0, 1, 0, 1), input: kdrange.New(0, 1), expected: false},
+		{name: "inside", r: kdrange.New(0, 4, 0, 4), input: kdrange.New(1, 2, 1, 4), expected: true},
+		{name: "equal", r: kdrange.New(0, 4, 0, 4), input: kdrange.New(0, 4, 0, 4), expected: true},
+		{name: "overlapping", r: kdrange.New(0, 4, 0, 4), input: kdrange.New(1, 5, 1, 2), expected: false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.r.ContainsRange(test.input))
+		})
+	}
+}
+
+func TestRange_Intersects(t *testing.T) {
+	tests := []struct {
+		name     string
+		r        kdrange.Range
+		input    kdrange.Range
+		expected bool
+	}{
+		{name: "wrong dim", r: kdrange.New(0, 1, 0, 1), input: kdrange.New(0, 1), expected: false},
+		{name: "overlapping", r: kdrange.New(0, 4, 0, 4), input: kdrange.New(1, 5, 1, 2), expected: true},
+		{name: "touching", r: kdrange.New(0, 4, 0, 4), input: kdrange.New(4, 5, 1, 2), expected: true},
+		{name: "disjoint x", r: kdrange.New(0, 4, 0, 4), input: kdrange.New(5, 6, 1, 2), expected: false},
+		{name: "disjoint y", r: kdrange.New(0, 4, 0, 4), input: kdrange.New(1, 2, -2, -1), expected: false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.r.Intersects(test.input))
+			assert.Equal(t, test.expected, test.input.Intersects(test.r))
+		})
+	}
+}
+
+func TestRange_Intersection(t *testing.T) {
+	tests := []struct {
+		name   string
+		r      kdrange.Range
+		input  kdrange.Range
+		output kdrange.Range
+	}{
+		{name: "wrong dim", r: kdrange.New(0, 1, 0, 1), input: kdrange.New(0, 1), output: nil},
+		{name: "disjoint", r: kdrange.New(0, 4, 0, 4), input: kdrange.New(5, 6, 1, 2), output: nil},
+		{name: "overlapping", r: kdrange.New(0, 4, 0, 4), input: kdrange.New(1, 5, -1, 2), output: kdrange.New(1, 4, 0, 2)},
+		{name: "touching", r: kdrange.New(0, 4, 0, 4), input: kdrange.New(4, 5, 1, 2), output: kdrange.New(4, 4, 1, 2)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.output, test.r.Intersection(test.input))
+		})
+	}
+}
+
+func TestRange_Union(t *testing.T) {
+	tests := []struct {
+		name   string
+		r      kdrange.Range
+		input  kdrange.Range
+		output kdrange.Range
+	}{
+		{name: "wrong dim", r: kdrange.New(0, 1, 0, 1), input: kdrange.New(0, 1), output: nil},
+		{name: "disjoint", r: kdrange.New(0, 4, 0, 4), input: kdrange.New(5, 6, 1, 2), output: kdrange.New(0, 6, 0, 4)},
+		{name: "inside", r: kdrange.New(0, 4, 0, 4), input: kdrange.New(1, 2, 1, 2), output: kdrange.New(0, 4, 0, 4)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.output, test.r.Union(test.input))
+		})
+	}
+}
+
+func TestRange_Expand(t *testing.T) {
+	tests := []struct {
+		name   string
+		r      kdrange.Range
+		by     float64
+		output kdrange.Range
+	}{
+		{name: "nil", r: nil, by: 1, output: nil},
+		{name: "grow", r: kdrange.New(0, 4, 1, 2), by: 1, output: kdrange.New(-1, 5, 0, 3)},
+		{name: "shrink", r: kdrange.New(0, 4, 1, 2), by: -1, output: kdrange.New(1, 3, 1.5, 1.5)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.output, test.r.Expand(test.by))
+		})
+	}
+}
+
+func TestRange_Volume(t *testing.T) {
+	tests := []struct {
+		name   string
+		r      kdrange.Range
+		output float64
+	}{
+		{name: "nil", r: nil, output: 0},
+		{name: "1d", r: kdrange.New(1, 4), output: 3},
+		{name: "2d", r: kdrange.New(0, 4, 1, 2), output: 4},
+		{name: "3d", r: kdrange.New(0, 4, 1, 2, -1, 1), output: 8},
+		{name: "flat", r: kdrange.New(0, 4, 1, 1), output: 0},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.output, test.r.Volume())
+		})
+	}
+}
+
+func TestRange_Center(t *testing.T) {
+	assert.Equal(t, []float64{}, kdrange.Range(nil).Center())
+	assert.Equal(t, []float64{2, 1.5}, kdrange.New(0, 4, 1, 2).Center())
+}
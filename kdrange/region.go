@@ -0,0 +1,178 @@
+/*
+ * Copyright 2020 Dennis Kuhnert
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package kdrange
+
+import "math"
+
+// Region represents an area in k-dimensional space that can be searched in a k-d tree.
+//
+// Range, Box and Partial are axis-aligned regions, Ball and Ellipsoid are round regions,
+// Polygon and Polytope are bounded by straight edges.
+type Region interface {
+	// Contains reports whether the point p lies inside the region.
+	Contains(p Point) bool
+	// Intersects reports whether the region overlaps with the axis-aligned box b.
+	// It may return true for boxes without any common point, this only costs performance.
+	Intersects(b Range) bool
+	// ContainsRange reports whether the axis-aligned box b lies completely inside the region.
+	// It may return false for boxes that are inside, this only costs performance.
+	ContainsRange(b Range) bool
+}
+
+// Ball represents a k-dimensional sphere. Points on the surface are inside the ball.
+// A nil Ball or one with a negative radius contains no points.
+type Ball struct {
+	Center []float64
+	Radius float64
+}
+
+// NewBall creates a new Ball around center with the given radius.
+func NewBall(center []float64, radius float64) *Ball {
+	return &Ball{Center: center, Radius: radius}
+}
+
+// Contains reports whether the point p lies inside the ball.
+func (b *Ball) Contains(p Point) bool {
+	if !b.valid() || p == nil || p.Dimensions() != len(b.Center) {
+		return false
+	}
+	sum := 0.
+	for dim, c := range b.Center {
+		d := p.Dimension(dim) - c
+		sum += d * d
+	}
+	return sum <= b.Radius*b.Radius
+}
+
+// Intersects reports whether the ball overlaps with the box r.
+func (b *Ball) Intersects(r Range) bool {
+	if !b.valid() || len(r) != len(b.Center) {
+		return false
+	}
+	sum := 0.
+	for dim, c := range b.Center {
+		d := c - clamp(c, r[dim])
+		sum += d * d
+	}
+	return sum <= b.Radius*b.Radius
+}
+
+// ContainsRange reports whether the box r lies completely inside the ball.
+func (b *Ball) ContainsRange(r Range) bool {
+	if !b.valid() || len(r) != len(b.Center) {
+		return false
+	}
+	sum := 0.
+	for dim, c := range b.Center {
+		d := farthest(c, r[dim])
+		sum += d * d
+	}
+	return sum <= b.Radius*b.Radius
+}
+
+// valid reports whether the ball is not nil and has a non-negative radius.
+func (b *Ball) valid() bool {
+	return b != nil && b.Radius >= 0
+}
+
+// Ellipsoid represents a k-dimensional axis-aligned ellipsoid.
+// Radii contains the semi-axis length of every dimension. Points on the surface are inside the ellipsoid.
+// A nil Ellipsoid, one whose center and radii differ in their dimensions or one with a negative radius contains no points.
+type Ellipsoid struct {
+	Center []float64
+	Radii  []float64
+}
+
+// NewEllipsoid creates a new Ellipsoid around center with the given semi-axis lengths.
+//
+// Returns nil if center and radii differ in their dimensions.
+func NewEllipsoid(center, radii []float64) *Ellipsoid {
+	if len(center) != len(radii) {
+		return nil
+	}
+	return &Ellipsoid{Center: center, Radii: radii}
+}
+
+// Contains reports whether the point p lies inside the ellipsoid.
+func (e *Ellipsoid) Contains(p Point) bool {
+	if !e.valid() || p == nil || p.Dimensions() != len(e.Center) {
+		return false
+	}
+	sum := 0.
+	for dim, c := range e.Center {
+		sum += scaledSquare(p.Dimension(dim)-c, e.Radii[dim])
+	}
+	return sum <= 1
+}
+
+// Intersects reports whether the ellipsoid overlaps with the box r.
+func (e *Ellipsoid) Intersects(r Range) bool {
+	if !e.valid() || len(r) != len(e.Center) {
+		return false
+	}
+	sum := 0.
+	for dim, c := range e.Center {
+		sum += scaledSquare(c-clamp(c, r[dim]), e.Radii[dim])
+	}
+	return sum <= 1
+}
+
+// ContainsRange reports whether the box r lies completely inside the ellipsoid.
+func (e *Ellipsoid) ContainsRange(r Range) bool {
+	if !e.valid() || len(r) != len(e.Center) {
+		return false
+	}
+	sum := 0.
+	for dim, c := range e.Center {
+		sum += scaledSquare(farthest(c, r[dim]), e.Radii[dim])
+	}
+	return sum <= 1
+}
+
+// valid reports whether the ellipsoid is not nil and has a non-negative radius for every dimension.
+func (e *Ellipsoid) valid() bool {
+	if e == nil || len(e.Radii) != len(e.Center) {
+		return false
+	}
+	for _, radius := range e.Radii {
+		if radius < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// clamp returns the value of limit closest to v.
+func clamp(v float64, limit [2]float64) float64 {
+	return math.Max(limit[0], math.Min(v, limit[1]))
+}
+
+// farthest returns the distance from v to the farthest value of limit.
+func farthest(v float64, limit [2]float64) float64 {
+	return math.Max(math.Abs(v-limit[0]), math.Abs(v-limit[1]))
+}
+
+// scaledSquare returns (d/radius)^2 while treating a zero radius as a degenerate axis.
+func scaledSquare(d, radius float64) float64 {
+	if radius == 0 {
+		if d == 0 {
+			return 0
+		}
+		return math.Inf(1)
+	}
+	return (d / radius) * (d / radius)
+}
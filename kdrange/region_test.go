@@ -0,0 +1,160 @@
+/*
+ * Copyright 2020 Dennis Kuhnert
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package kdrange_test
+
+import (
+	"github.com/kyroy/kdtree/kdrange"
+	"github.com/kyroy/kdtree/points"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestRegion_Contains(t *testing.T) {
+	tests := []struct {
+		name    string
+		region  kdrange.Region
+		inside  []kdrange.Point
+		outside []kdrange.Point
+	}{
+		{
+			name:    "range",
+			region:  kdrange.New(0, 2, 0, 1),
+			inside:  []kdrange.Point{&points.Point2D{X: 0, Y: 0}, &points.Point2D{X: 2, Y: 1}},
+			outside: []kdrange.Point{nil, &points.Point2D{X: 3, Y: 0}, &points.Point3D{}},
+		},
+		{
+			name:    "box",
+			region:  kdrange.NewBox(kdrange.GreaterThan(0), kdrange.AtMost(1)),
+			inside:  []kdrange.Point{&points.Point2D{X: 0.1, Y: -100}, &points.Point2D{X: 100, Y: 1}},
+			outside: []kdrange.Point{nil, &points.Point2D{X: 0, Y: 0}, &points.Point2D{X: 1, Y: 1.1}, &points.Point3D{X: 1}},
+		},
+		{
+			name:    "partial",
+			region:  kdrange.Partial{1: kdrange.Open(0, 1)},
+			inside:  []kdrange.Point{&points.Point2D{X: -100, Y: 0.5}, &points.Point3D{X: 100, Y: 0.5, Z: 7}},
+			outside: []kdrange.Point{nil, &points.Point2D{X: 0, Y: 1}, points.NewPoint([]float64{0.5}, nil)},
+		},
+		{
+			name:    "ball",
+			region:  kdrange.NewBall([]float64{1, 1}, 2),
+			inside:  []kdrange.Point{&points.Point2D{X: 1, Y: 1}, &points.Point2D{X: 3, Y: 1}, &points.Point2D{X: 2, Y: 2}},
+			outside: []kdrange.Point{nil, &points.Point2D{X: 3, Y: 3}, &points.Point3D{X: 1, Y: 1}},
+		},
+		{
+			name:    "ellipsoid",
+			region:  kdrange.NewEllipsoid([]float64{0, 0}, []float64{4, 1}),
+			inside:  []kdrange.Point{&points.Point2D{X: 4, Y: 0}, &points.Point2D{X: 0, Y: -1}, &points.Point2D{X: 2, Y: 0.5}},
+			outside: []kdrange.Point{nil, &points.Point2D{X: 0, Y: 2}, &points.Point2D{X: 4, Y: 0.1}, &points.Point3D{}},
+		},
+		{
+			name:    "flat ellipsoid",
+			region:  kdrange.NewEllipsoid([]float64{0, 0}, []float64{4, 0}),
+			inside:  []kdrange.Point{&points.Point2D{X: 4, Y: 0}, &points.Point2D{X: 0, Y: 0}},
+			outside: []kdrange.Point{&points.Point2D{X: 0, Y: 0.001}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for _, p := range test.inside {
+				assert.True(t, test.region.Contains(p), "should contain %v", p)
+			}
+			for _, p := range test.outside {
+				assert.False(t, test.region.Contains(p), "should not contain %v", p)
+			}
+		})
+	}
+}
+
+func TestRegion_Intersects(t *testing.T) {
+	tests := []struct {
+		name     string
+		region   kdrange.Region
+		inside   []kdrange.Range
+		overlaps []kdrange.Range
+		disjoint []kdrange.Range
+	}{
+		{
+			name:     "box",
+			region:   kdrange.NewBox(kdrange.GreaterThan(0), kdrange.AtMost(1)),
+			inside:   []kdrange.Range{kdrange.New(1, 2, -5, 1)},
+			overlaps: []kdrange.Range{kdrange.New(0, 2, -5, 1), kdrange.New(1, 2, 1, 2)},
+			disjoint: []kdrange.Range{kdrange.New(-1, 0, 0, 1), kdrange.New(1, 2, 1.5, 2), kdrange.New(1, 2)},
+		},
+		{
+			name:     "partial",
+			region:   kdrange.Partial{1: kdrange.Closed(0, 1)},
+			inside:   []kdrange.Range{kdrange.New(-100, 100, 0, 1)},
+			overlaps: []kdrange.Range{kdrange.New(-100, 100, 0.5, 2)},
+			disjoint: []kdrange.Range{kdrange.New(-100, 100, 2, 3), kdrange.New(0, 1)},
+		},
+		{
+			name:     "ball",
+			region:   kdrange.NewBall([]float64{0, 0}, 2),
+			inside:   []kdrange.Range{kdrange.New(-1, 1, -1, 1), kdrange.New(0, 2, 0, 0)},
+			overlaps: []kdrange.Range{kdrange.New(1, 3, 1, 3), kdrange.New(2, 3, -1, 1)},
+			disjoint: []kdrange.Range{kdrange.New(1.5, 3, 1.5, 3), kdrange.New(-1, 1)},
+		},
+		{
+			name:     "ellipsoid",
+			region:   kdrange.NewEllipsoid([]float64{0, 0}, []float64{4, 1}),
+			inside:   []kdrange.Range{kdrange.New(-2, 2, -0.5, 0.5)},
+			overlaps: []kdrange.Range{kdrange.New(3, 5, -1, 1), kdrange.New(-1, 1, 1, 2)},
+			disjoint: []kdrange.Range{kdrange.New(3, 5, 0.9, 1), kdrange.New(-1, 1, 1.1, 2)},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for _, r := range test.inside {
+				assert.True(t, test.region.Intersects(r), "should intersect %v", r)
+				assert.True(t, test.region.ContainsRange(r), "should contain %v", r)
+			}
+			for _, r := range test.overlaps {
+				assert.True(t, test.region.Intersects(r), "should intersect %v", r)
+				assert.False(t, test.region.ContainsRange(r), "should not contain %v", r)
+			}
+			for _, r := range test.disjoint {
+				assert.False(t, test.region.Intersects(r), "should not intersect %v", r)
+				assert.False(t, test.region.ContainsRange(r), "should not contain %v", r)
+			}
+		})
+	}
+}
+
+func TestNewEllipsoid(t *testing.T) {
+	assert.Nil(t, kdrange.NewEllipsoid([]float64{0, 0}, []float64{1}))
+	assert.Equal(t, &kdrange.Ellipsoid{Center: []float64{0, 0}, Radii: []float64{1, 2}}, kdrange.NewEllipsoid([]float64{0, 0}, []float64{1, 2}))
+}
+
+func TestRegion_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		region kdrange.Region
+	}{
+		{name: "nil ball", region: (*kdrange.Ball)(nil)},
+		{name: "nil ellipsoid", region: kdrange.NewEllipsoid([]float64{0, 0}, []float64{1})},
+		{name: "ellipsoid with missing radius", region: &kdrange.Ellipsoid{Center: []float64{0, 0}, Radii: []float64{1}}},
+		{name: "ball with negative radius", region: kdrange.NewBall([]float64{0, 0}, -0.2)},
+		{name: "ellipsoid with negative radius", region: kdrange.NewEllipsoid([]float64{0, 0}, []float64{1, -0.2})},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.False(t, test.region.Contains(&points.Point2D{X: 0, Y: 0}))
+			assert.False(t, test.region.Intersects(kdrange.New(-1, 1, -1, 1)))
+			assert.False(t, test.region.ContainsRange(kdrange.New(-0.1, 0.1, -0.1, 0.1)))
+		})
+	}
+}
@@ -22,7 +22,6 @@ import (
 	"github.com/kyroy/kdtree/kdrange"
 	"github.com/kyroy/priority-queue"
 	"math"
-	"sort"
 )
 
 // Point specifies one element of the k-d tree.
@@ -35,33 +34,38 @@ type Point interface {
 
 // KDTree represents the k-d tree.
 type KDTree struct {
-	root *node
+	root     *node
+	splitter Splitter
 }
 
+// Option configures a KDTree.
+type Option func(*KDTree)
+
 // New returns a balanced k-d tree.
-func New(points []Point) *KDTree {
-	return &KDTree{
-		root: newKDTree(points, 0),
-	}
+// By default, the axes are cycled through and the points are split at the median. See WithSplitter.
+func New(points []Point, options ...Option) *KDTree {
+	t := &KDTree{splitter: RoundRobin}
+	for _, option := range options {
+		option(t)
+	}
+	t.root = newKDTree(points, -1, t.splitter)
+	return t
 }
 
-func newKDTree(points []Point, axis int) *node {
+func newKDTree(points []Point, parentAxis int, splitter Splitter) *node {
 	if len(points) == 0 {
 		return nil
 	}
-	if len(points) == 1 {
-		return &node{Point: points[0]}
-	}
 
-	sort.Sort(&byDimension{dimension: axis, points: points})
-	mid := len(points) / 2
-	root := points[mid]
-	nextDim := (axis + 1) % root.Dimensions()
-	return &node{
-		Point: root,
-		Left:  newKDTree(points[:mid], nextDim),
-		Right: newKDTree(points[mid+1:], nextDim),
+	axis, mid := splitter.Split(points, parentAxis)
+	n := &node{
+		Point: points[mid],
+		Axis:  axis,
+		Left:  newKDTree(points[:mid], axis, splitter),
+		Right: newKDTree(points[mid+1:], axis, splitter),
 	}
+	n.update()
+	return n
 }
 
 // String returns a string representation of the k-d tree.
@@ -79,9 +83,9 @@ func printTreeNode(n *node) string {
 // Insert adds a point to the k-d tree.
 func (t *KDTree) Insert(p Point) {
 	if t.root == nil {
-		t.root = &node{Point: p}
+		t.root = newNode(p, 0)
 	} else {
-		t.root.Insert(p, 0)
+		t.root.Insert(p)
 	}
 }
 
@@ -91,7 +95,7 @@ func (t *KDTree) Remove(p Point) Point {
 	if t.root == nil || p == nil {
 		return nil
 	}
-	n, sub := t.root.Remove(p, 0)
+	n, sub := t.root.Remove(p)
 	if n == t.root {
 		t.root = sub
 	}
@@ -103,7 +107,7 @@ func (t *KDTree) Remove(p Point) Point {
 
 // Balance rebalances the k-d tree by recreating it.
 func (t *KDTree) Balance() {
-	t.root = newKDTree(t.Points(), 0)
+	t.root = newKDTree(t.Points(), -1, t.splitter)
 }
 
 // Points returns all points in the k-d tree.
@@ -117,36 +121,92 @@ func (t *KDTree) Points() []Point {
 
 // KNN returns the k-nearest neighbours of the given point.
 // The points are sorted by the distance to the given points. Starting with the nearest.
-func (t *KDTree) KNN(p Point, k int) []Point {
+// Subtrees are pruned with the distance to their bounding box.
+//
+// Statistics about the search are collected with WithQueryStats.
+func (t *KDTree) KNN(p Point, k int, options ...QueryOption) []Point {
 	if t.root == nil || p == nil || k == 0 {
 		return []Point{}
 	}
 
-	nearestPQ := pq.NewPriorityQueue(pq.WithMinPrioSize(k))
-	knn(p, k, t.root, 0, nearestPQ)
+	s := newKNNSearch(p, k)
+	s.search(t.root, 1)
+	newQuery(options).finish(&s.stats)
 
-	points := make([]Point, 0, k)
-	for i := 0; i < k && 0 < nearestPQ.Len(); i++ {
-		o := nearestPQ.PopLowest().(*node).Point
-		points = append(points, o)
-	}
+	return popPoints(s.nearest, k)
+}
 
+// RangeSearch returns all points in the given region r.
+// r can be any kdrange.Region, e.g. a closed kdrange.Range, a kdrange.Box with open or unbounded intervals,
+// a kdrange.Partial that only constrains some dimensions or a round kdrange.Ball.
+//
+// Statistics about the search are collected with WithQueryStats.
+//
+// Returns an empty slice when input is nil or r does not match the tree's dimensions.
+func (t *KDTree) RangeSearch(r kdrange.Region, options ...QueryOption) []Point {
+	points := []Point{}
+	t.RangeVisit(r, func(p Point) bool {
+		points = append(points, p)
+		return true
+	}, options...)
 	return points
 }
 
-// RangeSearch returns all points in the given range r.
+// RangeVisit calls fn for every point in the given region r.
+// The search stops as soon as fn returns false.
 //
-// Returns an empty slice when input is nil or len(r) does not equal Point.Dimensions().
-func (t *KDTree) RangeSearch(r kdrange.Range) []Point {
-	if t.root == nil || r == nil || len(r) != t.root.Dimensions() {
-		return []Point{}
+// Statistics about the search are collected with WithQueryStats.
+//
+// fn is not called when input is nil or r does not match the tree's dimensions.
+func (t *KDTree) RangeVisit(r kdrange.Region, fn func(Point) bool, options ...QueryOption) {
+	if t.root == nil || r == nil || fn == nil {
+		return
+	}
+
+	var stats QueryStats
+	t.root.RangeVisit(r, fn, &stats, 1)
+	newQuery(options).finish(&stats)
+}
+
+// RangeCount returns the number of points in the given region r.
+// Subtrees that are completely inside the region are counted without being traversed.
+//
+// Returns 0 when input is nil or r does not match the tree's dimensions.
+func (t *KDTree) RangeCount(r kdrange.Region) int {
+	if t.root == nil || r == nil {
+		return 0
+	}
+
+	return t.root.RangeCount(r)
+}
+
+// RadiusCount returns the number of points whose distance to the given point p is at most radius.
+// Subtrees that are completely inside the radius are counted without being traversed.
+//
+// Returns 0 when p is nil or p.Dimensions() does not equal the dimensions of the tree's points.
+func (t *KDTree) RadiusCount(p Point, radius float64) int {
+	if t.root == nil || p == nil || p.Dimensions() != t.root.Dimensions() || radius < 0 {
+		return 0
 	}
 
-	return t.root.RangeSearch(r, 0)
+	return t.root.RadiusCount(p, radius*radius)
+}
+
+// knnSearch holds the state of a k-nearest neighbour search.
+type knnSearch struct {
+	p       Point
+	k       int
+	nearest *pq.PriorityQueue
+	stats   QueryStats
 }
 
-func knn(p Point, k int, start *node, currentAxis int, nearestPQ *pq.PriorityQueue) {
-	if p == nil || k == 0 || start == nil {
+func newKNNSearch(p Point, k int) *knnSearch {
+	return &knnSearch{p: p, k: k, nearest: pq.NewPriorityQueue(pq.WithMinPrioSize(k))}
+}
+
+// search adds the nearest neighbours in the subtree start at the given depth to the queue.
+func (s *knnSearch) search(start *node, depth int) {
+	if s.p == nil || s.k == 0 || start == nil {
 		return
 	}
 
@@ -156,35 +216,34 @@ func knn(p Point, k int, start *node, currentAxis int, nearestPQ *pq.PriorityQue
 	// 1. move down
 	for currentNode != nil {
 		path = append(path, currentNode)
-		if p.Dimension(currentAxis) < currentNode.Dimension(currentAxis) {
+		if s.p.Dimension(currentNode.Axis) < currentNode.Dimension(currentNode.Axis) {
 			currentNode = currentNode.Left
 		} else {
 			currentNode = currentNode.Right
 		}
-		currentAxis = (currentAxis + 1) % p.Dimensions()
 	}
 
 	// 2. move up
-	currentAxis = (currentAxis - 1 + p.Dimensions()) % p.Dimensions()
 	for path, currentNode = popLast(path); currentNode != nil; path, currentNode = popLast(path) {
-		currentDistance := distance(p, currentNode)
-		checkedDistance := getKthOrLastDistance(nearestPQ, k-1)
+		currentDepth := depth + len(path)
+		s.stats.visit(currentDepth)
+		s.stats.DistanceComputations++
+		currentDistance := distance(s.p, currentNode)
+		checkedDistance := getKthOrLastDistance(s.nearest, s.k-1)
 		if currentDistance < checkedDistance {
-			nearestPQ.Insert(currentNode, currentDistance)
-			checkedDistance = getKthOrLastDistance(nearestPQ, k-1)
+			s.stats.QueueInserts++
+			s.nearest.Insert(currentNode, currentDistance)
+			checkedDistance = getKthOrLastDistance(s.nearest, s.k-1)
 		}
 
-		// check other side of plane
-		if planeDistance(p, currentNode.Dimension(currentAxis), currentAxis) < checkedDistance {
-			var next *node
-			if p.Dimension(currentAxis) < currentNode.Dimension(currentAxis) {
-				next = currentNode.Right
-			} else {
-				next = currentNode.Left
-			}
-			knn(p, k, next, (currentAxis+1)%p.Dimensions(), nearestPQ)
+		// check the other side, its bounding box is at least as far away as the splitting plane
+		next := currentNode.Left
+		if s.p.Dimension(currentNode.Axis) < currentNode.Dimension(currentNode.Axis) {
+			next = currentNode.Right
+		}
+		if next != nil && math.Sqrt(minSquaredDistance(s.p, next.Bounds)) < checkedDistance {
+			s.search(next, currentDepth+1)
 		}
-		currentAxis = (currentAxis - 1 + p.Dimensions()) % p.Dimensions()
 	}
 }
 
@@ -196,10 +255,6 @@ func distance(p1, p2 Point) float64 {
 	return math.Sqrt(sum)
 }
 
-func planeDistance(p Point, planePosition float64, dim int) float64 {
-	return math.Abs(planePosition - p.Dimension(dim))
-}
-
 func popLast(arr []*node) ([]*node, *node) {
 	l := len(arr) - 1
 	if l < 0 {
@@ -208,6 +263,16 @@ func popLast(arr []*node) ([]*node, *node) {
 	return arr[:l], arr[l]
 }
 
+// popPoints pops up to k nodes from the queue and returns their points, starting with the lowest priority.
+func popPoints(nearestPQ *pq.PriorityQueue, k int) []Point {
+	points := make([]Point, 0, k)
+	for i := 0; i < k && 0 < nearestPQ.Len(); i++ {
+		o := nearestPQ.PopLowest().(*node).Point
+		points = append(points, o)
+	}
+	return points
+}
+
 func getKthOrLastDistance(nearestPQ *pq.PriorityQueue, i int) float64 {
 	if nearestPQ.Len() <= i {
 		return math.MaxFloat64
@@ -249,8 +314,58 @@ func (b *byDimension) Swap(i, j int) {
 
 type node struct {
 	Point
+	// Axis is the dimension that splits the subtree.
+	Axis  int
 	Left  *node
 	Right *node
+	// Size is the number of points in the subtree.
+	Size int
+	// Bounds is the bounding box of all points in the subtree.
+	Bounds kdrange.Range
+	// Sum is the sum of the coordinates of all points in the subtree.
+	Sum []float64
+}
+
+func newNode(p Point, axis int) *node {
+	n := &node{Point: p, Axis: axis}
+	n.update()
+	return n
+}
+
+// update recomputes Size, Bounds and Sum from the node's point and its children.
+func (n *node) update() {
+	dims := n.Dimensions()
+	if len(n.Bounds) != dims {
+		n.Bounds = make(kdrange.Range, dims)
+		n.Sum = make([]float64, dims)
+	}
+	for i := 0; i < dims; i++ {
+		v := n.Dimension(i)
+		n.Bounds[i] = [2]float64{v, v}
+		n.Sum[i] = v
+	}
+	n.Size = 1
+	for _, child := range [2]*node{n.Left, n.Right} {
+		if child == nil {
+			continue
+		}
+		n.Size += child.Size
+		for i := 0; i < dims; i++ {
+			n.Bounds[i][0] = math.Min(n.Bounds[i][0], child.Bounds[i][0])
+			n.Bounds[i][1] = math.Max(n.Bounds[i][1], child.Bounds[i][1])
+			n.Sum[i] += child.Sum[i]
+		}
+	}
+}
+
+// extend adds the point p to the aggregates of n.
+func (n *node) extend(p Point) {
+	n.Size++
+	for i := range n.Bounds {
+		n.Bounds[i][0] = math.Min(n.Bounds[i][0], p.Dimension(i))
+		n.Bounds[i][1] = math.Max(n.Bounds[i][1], p.Dimension(i))
+		n.Sum[i] += p.Dimension(i)
+	}
 }
 
 func (n *node) String() string {
@@ -269,41 +384,46 @@ func (n *node) Points() []Point {
 	return points
 }
 
-func (n *node) Insert(p Point, axis int) {
-	if p.Dimension(axis) < n.Point.Dimension(axis) {
+// Insert adds p to the subtree. New leaves split at the axis following the one of their parent.
+func (n *node) Insert(p Point) {
+	n.extend(p)
+	nextAxis := (n.Axis + 1) % n.Point.Dimensions()
+	if p.Dimension(n.Axis) < n.Point.Dimension(n.Axis) {
 		if n.Left == nil {
-			n.Left = &node{Point: p}
+			n.Left = newNode(p, nextAxis)
 		} else {
-			n.Left.Insert(p, (axis+1)%n.Point.Dimensions())
+			n.Left.Insert(p)
 		}
 	} else {
 		if n.Right == nil {
-			n.Right = &node{Point: p}
+			n.Right = newNode(p, nextAxis)
 		} else {
-			n.Right.Insert(p, (axis+1)%n.Point.Dimensions())
+			n.Right.Insert(p)
 		}
 	}
 }
 
 // Remove returns (returned node, substitute node)
-func (n *node) Remove(p Point, axis int) (*node, *node) {
+func (n *node) Remove(p Point) (*node, *node) {
 	for i := 0; i < n.Dimensions(); i++ {
 		if n.Dimension(i) != p.Dimension(i) {
 			if n.Left != nil {
-				returnedNode, substitutedNode := n.Left.Remove(p, (axis+1)%n.Dimensions())
+				returnedNode, substitutedNode := n.Left.Remove(p)
 				if returnedNode != nil {
 					if returnedNode == n.Left {
 						n.Left = substitutedNode
 					}
+					n.update()
 					return returnedNode, nil
 				}
 			}
 			if n.Right != nil {
-				returnedNode, substitutedNode := n.Right.Remove(p, (axis+1)%n.Dimensions())
+				returnedNode, substitutedNode := n.Right.Remove(p)
 				if returnedNode != nil {
 					if returnedNode == n.Right {
 						n.Right = substitutedNode
 					}
+					n.update()
 					return returnedNode, nil
 				}
 			}
@@ -314,26 +434,30 @@ func (n *node) Remove(p Point, axis int) (*node, *node) {
 	// equals, remove n
 
 	if n.Left != nil {
-		largest := n.Left.FindLargest(axis, nil)
-		removed, sub := n.Left.Remove(largest, (axis+1)%n.Dimensions())
+		largest := n.Left.FindLargest(n.Axis, nil)
+		removed, sub := n.Left.Remove(largest)
 
+		removed.Axis = n.Axis
 		removed.Left = n.Left
 		removed.Right = n.Right
 		if n.Left == removed {
 			removed.Left = sub
 		}
+		removed.update()
 		return n, removed
 	}
 
 	if n.Right != nil {
-		smallest := n.Right.FindSmallest(axis, nil)
-		removed, sub := n.Right.Remove(smallest, (axis+1)%n.Dimensions())
+		smallest := n.Right.FindSmallest(n.Axis, nil)
+		removed, sub := n.Right.Remove(smallest)
 
+		removed.Axis = n.Axis
 		removed.Left = n.Left
 		removed.Right = n.Right
 		if n.Right == removed {
 			removed.Right = sub
 		}
+		removed.update()
 		return n, removed
 	}
 
@@ -367,23 +491,123 @@ func (n *node) FindLargest(axis int, largest *node) *node {
 	return largest
 }
 
-func (n *node) RangeSearch(r kdrange.Range, axis int) []Point {
-	points := []Point{}
+// Visit calls fn for all points of the subtree, whose root has the given depth.
+// Returns false if fn stopped the traversal.
+func (n *node) Visit(fn func(Point) bool, s *QueryStats, depth int) bool {
+	s.visit(depth)
+	if !fn(n.Point) {
+		return false
+	}
+	if n.Left != nil && !n.Left.Visit(fn, s, depth+1) {
+		return false
+	}
+	if n.Right != nil && !n.Right.Visit(fn, s, depth+1) {
+		return false
+	}
+	return true
+}
 
-	for dim, limit := range r {
-		if limit[0] > n.Dimension(dim) || limit[1] < n.Dimension(dim) {
-			goto checkChildren
-		}
+// RangeVisit calls fn for all points of the subtree in the region r.
+// Returns false if fn stopped the search.
+func (n *node) RangeVisit(r kdrange.Region, fn func(Point) bool, s *QueryStats, depth int) bool {
+	if !r.Intersects(n.Bounds) {
+		return true
+	}
+	if r.ContainsRange(n.Bounds) {
+		return n.Visit(fn, s, depth)
 	}
-	points = append(points, n.Point)
 
-checkChildren:
-	if n.Left != nil && n.Dimension(axis) >= r[axis][0] {
-		points = append(points, n.Left.RangeSearch(r, (axis+1)%n.Dimensions())...)
+	s.visit(depth)
+	if r.Contains(n.Point) && !fn(n.Point) {
+		return false
 	}
-	if n.Right != nil && n.Dimension(axis) <= r[axis][1] {
-		points = append(points, n.Right.RangeSearch(r, (axis+1)%n.Dimensions())...)
+	if n.Left != nil && !n.Left.RangeVisit(r, fn, s, depth+1) {
+		return false
 	}
+	if n.Right != nil && !n.Right.RangeVisit(r, fn, s, depth+1) {
+		return false
+	}
+	return true
+}
 
-	return points
+func (n *node) RangeCount(r kdrange.Region) int {
+	if !r.Intersects(n.Bounds) {
+		return 0
+	}
+	if r.ContainsRange(n.Bounds) {
+		return n.Size
+	}
+
+	count := 0
+	if r.Contains(n.Point) {
+		count++
+	}
+	if n.Left != nil {
+		count += n.Left.RangeCount(r)
+	}
+	if n.Right != nil {
+		count += n.Right.RangeCount(r)
+	}
+	return count
+}
+
+// RadiusCount counts the points within the squared distance sqRadius to p.
+func (n *node) RadiusCount(p Point, sqRadius float64) int {
+	if minSquaredDistance(p, n.Bounds) > sqRadius {
+		return 0
+	}
+	if maxSquaredDistance(p, n.Bounds) <= sqRadius {
+		return n.Size
+	}
+
+	count := 0
+	if squaredDistance(p, n.Point) <= sqRadius {
+		count++
+	}
+	if n.Left != nil {
+		count += n.Left.RadiusCount(p, sqRadius)
+	}
+	if n.Right != nil {
+		count += n.Right.RadiusCount(p, sqRadius)
+	}
+	return count
+}
+
+//
+//
+// geometry helpers
+//
+
+func squaredDistance(p1, p2 Point) float64 {
+	sum := 0.
+	for i := 0; i < p1.Dimensions(); i++ {
+		d := p1.Dimension(i) - p2.Dimension(i)
+		sum += d * d
+	}
+	return sum
+}
+
+// minSquaredDistance returns the squared distance from p to the nearest point of the box b.
+func minSquaredDistance(p Point, b kdrange.Range) float64 {
+	sum := 0.
+	for dim, limit := range b {
+		v := p.Dimension(dim)
+		if v < limit[0] {
+			sum += (limit[0] - v) * (limit[0] - v)
+		} else if v > limit[1] {
+			sum += (v - limit[1]) * (v - limit[1])
+		}
+	}
+	return sum
+}
+
+// maxSquaredDistance returns the squared distance from p to the farthest point of the box b.
+func maxSquaredDistance(p Point, b kdrange.Range) float64 {
+	sum := 0.
+	for dim, limit := range b {
+		v := p.Dimension(dim)
+		d := math.Max(math.Abs(v-limit[0]), math.Abs(v-limit[1]))
+		sum += d * d
+	}
+	return sum
 }
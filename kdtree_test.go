@@ -496,6 +496,380 @@ func TestKDTree_RangeSearchWithGenerator(t *testing.T) {
 	}
 }
 
+func TestKDTree_RangeSearchBox(t *testing.T) {
+	input := []kdtree.Point{&Point2D{X: 1, Y: 3}, &Point2D{X: 1, Y: 8}, &Point2D{X: 2, Y: 2}, &Point2D{X: 2, Y: 10}, &Point2D{X: 3, Y: 6}, &Point2D{X: 4, Y: 1}, &Point2D{X: 5, Y: 4}, &Point2D{X: 6, Y: 8}, &Point2D{X: 7, Y: 4}, &Point2D{X: 7, Y: 7}, &Point2D{X: 8, Y: 2}, &Point2D{X: 8, Y: 5}, &Point2D{X: 9, Y: 9}}
+	tests := []struct {
+		name     string
+		input    kdrange.Box
+		expected []kdtree.Point
+	}{
+		{
+			name:     "wrong dim",
+			input:    kdrange.NewBox(kdrange.Unbounded()),
+			expected: []kdtree.Point{},
+		},
+		{
+			name:     "unbounded",
+			input:    kdrange.NewBox(kdrange.Unbounded(), kdrange.Unbounded()),
+			expected: input,
+		},
+		{
+			name:     "x > 7",
+			input:    kdrange.NewBox(kdrange.GreaterThan(7), kdrange.Unbounded()),
+			expected: []kdtree.Point{&Point2D{X: 8, Y: 2}, &Point2D{X: 8, Y: 5}, &Point2D{X: 9, Y: 9}},
+		},
+		{
+			name:     "x >= 7",
+			input:    kdrange.NewBox(kdrange.AtLeast(7), kdrange.Unbounded()),
+			expected: []kdtree.Point{&Point2D{X: 7, Y: 4}, &Point2D{X: 7, Y: 7}, &Point2D{X: 8, Y: 2}, &Point2D{X: 8, Y: 5}, &Point2D{X: 9, Y: 9}},
+		},
+		{
+			name:     "1 < x < 5, y <= 2",
+			input:    kdrange.NewBox(kdrange.Open(1, 5), kdrange.AtMost(2)),
+			expected: []kdtree.Point{&Point2D{X: 2, Y: 2}, &Point2D{X: 4, Y: 1}},
+		},
+		{
+			name:     "2 <= x < 4, 2 < y <= 10",
+			input:    kdrange.NewBox(kdrange.ClosedOpen(2, 4), kdrange.OpenClosed(2, 10)),
+			expected: []kdtree.Point{&Point2D{X: 2, Y: 10}, &Point2D{X: 3, Y: 6}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tree := kdtree.New(append([]kdtree.Point(nil), input...))
+			assert.ElementsMatch(t, test.expected, tree.RangeSearch(test.input))
+			assert.Equal(t, len(test.expected), tree.RangeCount(test.input))
+		})
+	}
+}
+
+func TestKDTree_RangeSearchBoxWithGenerator(t *testing.T) {
+	input := generateTestCaseData(10000)
+	tree := kdtree.New(input)
+	box := kdrange.NewBox(kdrange.GreaterThan(-300), kdrange.LessThan(200))
+	var expected []kdtree.Point
+	for _, p := range input {
+		if p.Dimension(0) > -300 && p.Dimension(1) < 200 {
+			expected = append(expected, p)
+		}
+	}
+	assert.ElementsMatch(t, expected, tree.RangeSearch(box))
+	assert.Equal(t, len(expected), tree.RangeCount(box))
+}
+
+func TestKDTree_RangeSearchPartial(t *testing.T) {
+	input := make([]kdtree.Point, 0, 5000)
+	for i := 0; i < cap(input); i++ {
+		input = append(input, generateTestPoint(3))
+	}
+	tests := []struct {
+		name   string
+		input  kdrange.Partial
+		filter func(p kdtree.Point) bool
+	}{
+		{name: "nil", input: nil, filter: func(p kdtree.Point) bool { return true }},
+		{name: "out of bounds", input: kdrange.Partial{3: kdrange.Closed(0, 1)}, filter: func(p kdtree.Point) bool { return false }},
+		{
+			name:   "dim 2",
+			input:  kdrange.Partial{2: kdrange.ClosedOpen(-200, 300)},
+			filter: func(p kdtree.Point) bool { return p.Dimension(2) >= -200 && p.Dimension(2) < 300 },
+		},
+		{
+			name:   "dim 0 and 2",
+			input:  kdrange.Partial{0: kdrange.GreaterThan(0), 2: kdrange.AtMost(-500)},
+			filter: func(p kdtree.Point) bool { return p.Dimension(0) > 0 && p.Dimension(2) <= -500 },
+		},
+	}
+	tree := kdtree.New(append([]kdtree.Point(nil), input...))
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			expected := []kdtree.Point{}
+			for _, p := range input {
+				if test.filter(p) {
+					expected = append(expected, p)
+				}
+			}
+			assert.ElementsMatch(t, expected, tree.RangeSearch(test.input))
+			assert.Equal(t, len(expected), tree.RangeCount(test.input))
+		})
+	}
+}
+
+func TestKDTree_RangeVisitPartialAllocations(t *testing.T) {
+	input := make([]kdtree.Point, 0, 5000)
+	for i := 0; i < cap(input); i++ {
+		input = append(input, generateTestPoint(3))
+	}
+	tree := kdtree.New(input)
+	partial := kdrange.Partial{0: kdrange.Closed(-100, 100)}
+	box := kdrange.NewBox(kdrange.Closed(-100, 100), kdrange.Unbounded(), kdrange.Unbounded())
+
+	count := func(r kdrange.Region) func() {
+		return func() {
+			tree.RangeVisit(r, func(p kdtree.Point) bool { return true })
+		}
+	}
+	assert.Equal(t, testing.AllocsPerRun(10, count(box)), testing.AllocsPerRun(10, count(partial)))
+}
+
+func TestKDTree_RangeSearchRegionWithGenerator(t *testing.T) {
+	input := generateTestCaseData(10000)
+	tests := []struct {
+		name   string
+		region kdrange.Region
+		filter func(p kdtree.Point) bool
+	}{
+		{
+			name:   "ball",
+			region: kdrange.NewBall([]float64{100, -200}, 600),
+			filter: func(p kdtree.Point) bool {
+				return distance(p, &Point2D{X: 100, Y: -200}) <= 600
+			},
+		},
+		{
+			name:   "ellipsoid",
+			region: kdrange.NewEllipsoid([]float64{100, -200}, []float64{1000, 300}),
+			filter: func(p kdtree.Point) bool {
+				dx, dy := (p.Dimension(0)-100)/1000, (p.Dimension(1)+200)/300
+				return dx*dx+dy*dy <= 1
+			},
+		},
+		{
+			name:   "ball wrong dim",
+			region: kdrange.NewBall([]float64{100, -200, 0}, 600),
+			filter: func(p kdtree.Point) bool { return false },
+		},
+		{
+			name:   "nil ball",
+			region: (*kdrange.Ball)(nil),
+			filter: func(p kdtree.Point) bool { return false },
+		},
+		{
+			name:   "invalid ellipsoid",
+			region: kdrange.NewEllipsoid([]float64{100, -200}, []float64{1000}),
+			filter: func(p kdtree.Point) bool { return false },
+		},
+	}
+	tree := kdtree.New(append([]kdtree.Point(nil), input...))
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			expected := []kdtree.Point{}
+			for _, p := range input {
+				if test.filter(p) {
+					expected = append(expected, p)
+				}
+			}
+			assert.ElementsMatch(t, expected, tree.RangeSearch(test.region))
+			assert.Equal(t, len(expected), tree.RangeCount(test.region))
+		})
+	}
+}
+
+func TestKDTree_RangeSearchPolygonWithGenerator(t *testing.T) {
+	input := generateTestCaseData(10000)
+	// star with 5 spikes
+	var vertices [][2]float64
+	for i := 0; i < 10; i++ {
+		radius := 1400.
+		if i%2 == 1 {
+			radius = 500
+		}
+		angle := float64(i) * math.Pi / 5
+		vertices = append(vertices, [2]float64{radius * math.Cos(angle), radius * math.Sin(angle)})
+	}
+	polygon := kdrange.NewPolygon(vertices...)
+
+	expected := []kdtree.Point{}
+	for _, p := range input {
+		if polygon.Contains(p) {
+			expected = append(expected, p)
+		}
+	}
+	tree := kdtree.New(input)
+	assert.ElementsMatch(t, expected, tree.RangeSearch(polygon))
+	assert.Equal(t, len(expected), tree.RangeCount(polygon))
+	assert.ElementsMatch(t, expected, tree.RangeSearch(&kdrange.Polygon{Vertices: vertices}))
+	assert.Equal(t, []kdtree.Point{}, tree.RangeSearch(kdrange.NewPolygon(vertices[:2]...)))
+}
+
+func TestKDTree_RangeSearchPolytopeWithGenerator(t *testing.T) {
+	input := make([]kdtree.Point, 0, 5000)
+	for i := 0; i < cap(input); i++ {
+		input = append(input, generateTestPoint(3))
+	}
+	// octahedron |x| + |y| + |z| <= 1500
+	var halfSpaces []kdrange.HalfSpace
+	for _, x := range []float64{-1, 1} {
+		for _, y := range []float64{-1, 1} {
+			for _, z := range []float64{-1, 1} {
+				halfSpaces = append(halfSpaces, kdrange.HalfSpace{Normal: []float64{x, y, z}, Offset: 1500})
+			}
+		}
+	}
+	polytope := kdrange.NewPolytope(halfSpaces...)
+
+	expected := []kdtree.Point{}
+	for _, p := range input {
+		if math.Abs(p.Dimension(0))+math.Abs(p.Dimension(1))+math.Abs(p.Dimension(2)) <= 1500 {
+			expected = append(expected, p)
+		}
+	}
+	tree := kdtree.New(append([]kdtree.Point(nil), input...))
+	assert.ElementsMatch(t, expected, tree.RangeSearch(polytope))
+	assert.Equal(t, len(expected), tree.RangeCount(polytope))
+}
+
+func TestKDTree_RangeVisit(t *testing.T) {
+	tests := []struct {
+		name     string
+		tree     *kdtree.KDTree
+		input    kdrange.Range
+		limit    int
+		expected int
+	}{
+		{name: "nil", tree: kdtree.New(generateTestCaseData(5)), input: nil, limit: 10, expected: 0},
+		{name: "wrong dim", tree: kdtree.New(generateTestCaseData(5)), input: kdrange.New(), limit: 10, expected: 0},
+		{name: "empty tree", tree: kdtree.New(nil), input: kdrange.New(0, 1, 0, 1), limit: 10, expected: 0},
+		{
+			name:     "all",
+			tree:     kdtree.New([]kdtree.Point{&Point2D{X: 1, Y: 3}, &Point2D{X: 1, Y: 8}, &Point2D{X: 2, Y: 2}, &Point2D{X: 2, Y: 10}, &Point2D{X: 3, Y: 6}, &Point2D{X: 4, Y: 1}, &Point2D{X: 5, Y: 4}, &Point2D{X: 6, Y: 8}, &Point2D{X: 7, Y: 4}, &Point2D{X: 7, Y: 7}, &Point2D{X: 8, Y: 2}, &Point2D{X: 8, Y: 5}, &Point2D{X: 9, Y: 9}}),
+			input:    kdrange.New(2, 7, 2, 7),
+			limit:    10,
+			expected: 5,
+		},
+		{
+			name:     "stop early",
+			tree:     kdtree.New([]kdtree.Point{&Point2D{X: 1, Y: 3}, &Point2D{X: 1, Y: 8}, &Point2D{X: 2, Y: 2}, &Point2D{X: 2, Y: 10}, &Point2D{X: 3, Y: 6}, &Point2D{X: 4, Y: 1}, &Point2D{X: 5, Y: 4}, &Point2D{X: 6, Y: 8}, &Point2D{X: 7, Y: 4}, &Point2D{X: 7, Y: 7}, &Point2D{X: 8, Y: 2}, &Point2D{X: 8, Y: 5}, &Point2D{X: 9, Y: 9}}),
+			input:    kdrange.New(2, 7, 2, 7),
+			limit:    2,
+			expected: 2,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var visited []kdtree.Point
+			test.tree.RangeVisit(test.input, func(p kdtree.Point) bool {
+				visited = append(visited, p)
+				return len(visited) < test.limit
+			})
+			assert.Len(t, visited, test.expected)
+			for _, p := range visited {
+				assert.Len(t, filterRangeSearch([]kdtree.Point{p}, test.input), 1)
+			}
+		})
+	}
+}
+
+func TestKDTree_RangeCount(t *testing.T) {
+	tests := []struct {
+		name     string
+		tree     *kdtree.KDTree
+		input    kdrange.Range
+		expected int
+	}{
+		{name: "nil", tree: kdtree.New(generateTestCaseData(5)), input: nil, expected: 0},
+		{name: "wrong dim", tree: kdtree.New(generateTestCaseData(5)), input: kdrange.New(), expected: 0},
+		{name: "empty tree", tree: kdtree.New(nil), input: kdrange.New(0, 1, 0, 1), expected: 0},
+		{
+			name:     "small 2D example",
+			tree:     kdtree.New([]kdtree.Point{&Point2D{X: 1, Y: 3}, &Point2D{X: 1, Y: 8}, &Point2D{X: 2, Y: 2}, &Point2D{X: 2, Y: 10}, &Point2D{X: 3, Y: 6}, &Point2D{X: 4, Y: 1}, &Point2D{X: 5, Y: 4}, &Point2D{X: 6, Y: 8}, &Point2D{X: 7, Y: 4}, &Point2D{X: 7, Y: 7}, &Point2D{X: 8, Y: 2}, &Point2D{X: 8, Y: 5}, &Point2D{X: 9, Y: 9}}),
+			input:    kdrange.New(2, 7, 2, 7),
+			expected: 5,
+		},
+		{
+			name:     "everything",
+			tree:     kdtree.New([]kdtree.Point{&Point2D{X: 1, Y: 3}, &Point2D{X: 1, Y: 8}, &Point2D{X: 2, Y: 2}, &Point2D{X: 2, Y: 10}, &Point2D{X: 3, Y: 6}, &Point2D{X: 4, Y: 1}, &Point2D{X: 5, Y: 4}, &Point2D{X: 6, Y: 8}, &Point2D{X: 7, Y: 4}, &Point2D{X: 7, Y: 7}, &Point2D{X: 8, Y: 2}, &Point2D{X: 8, Y: 5}, &Point2D{X: 9, Y: 9}}),
+			input:    kdrange.New(0, 10, 0, 10),
+			expected: 13,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.tree.RangeCount(test.input))
+		})
+	}
+}
+
+func TestKDTree_RangeCountWithGenerator(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []kdtree.Point
+		r     kdrange.Range
+	}{
+		{name: "nodes: 100 range: -100 50 -50 100", input: generateTestCaseData(100), r: kdrange.New(-100, 50, -50, 100)},
+		{name: "nodes: 10000 range: -500 250 -250 500", input: generateTestCaseData(10000), r: kdrange.New(-500, 250, -250, 500)},
+		{name: "nodes: 100000 range: -1000 1000 -1000 1000", input: generateTestCaseData(100000), r: kdrange.New(-1000, 1000, -1000, 1000)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tree := kdtree.New(test.input)
+			assert.Equal(t, len(filterRangeSearch(test.input, test.r)), tree.RangeCount(test.r))
+		})
+	}
+}
+
+func TestKDTree_RadiusCount(t *testing.T) {
+	tests := []struct {
+		name     string
+		tree     *kdtree.KDTree
+		target   kdtree.Point
+		radius   float64
+		expected int
+	}{
+		{name: "nil", tree: kdtree.New(generateTestCaseData(5)), target: nil, radius: 10, expected: 0},
+		{name: "wrong dim", tree: kdtree.New(generateTestCaseData(5)), target: NewPoint([]float64{1}, nil), radius: 10, expected: 0},
+		{name: "empty tree", tree: kdtree.New(nil), target: &Point2D{}, radius: 10, expected: 0},
+		{
+			name:     "small 2D example",
+			tree:     kdtree.New([]kdtree.Point{&Point2D{X: 1, Y: 3}, &Point2D{X: 1, Y: 8}, &Point2D{X: 2, Y: 2}, &Point2D{X: 2, Y: 10}, &Point2D{X: 3, Y: 6}, &Point2D{X: 4, Y: 1}, &Point2D{X: 5, Y: 4}, &Point2D{X: 6, Y: 8}, &Point2D{X: 7, Y: 4}, &Point2D{X: 7, Y: 7}, &Point2D{X: 8, Y: 2}, &Point2D{X: 8, Y: 5}, &Point2D{X: 9, Y: 9}}),
+			target:   &Point2D{X: 8, Y: 4},
+			radius:   2,
+			expected: 3,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.tree.RadiusCount(test.target, test.radius))
+		})
+	}
+}
+
+func TestKDTree_RadiusCountWithGenerator(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  []kdtree.Point
+		target kdtree.Point
+		radius float64
+	}{
+		{name: "p:100,r:100", input: generateTestCaseData(100), target: &Point2D{}, radius: 100},
+		{name: "p:10000,r:500", input: generateTestCaseData(10000), target: &Point2D{X: 200, Y: -100}, radius: 500},
+		{name: "p:100000,r:1000", input: generateTestCaseData(100000), target: &Point2D{}, radius: 1000},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tree := kdtree.New(test.input)
+			assert.Equal(t, len(filterRadiusSearch(test.input, test.target, test.radius)), tree.RadiusCount(test.target, test.radius))
+		})
+	}
+}
+
+func TestKDTree_CountAfterInsertRemove(t *testing.T) {
+	input := generateTestCaseData(1000)
+	tree := kdtree.New(input[:500])
+	for _, p := range input[500:] {
+		tree.Insert(p)
+	}
+	for _, p := range input[:300] {
+		tree.Remove(p)
+	}
+	remaining := input[300:]
+
+	r := kdrange.New(-800, 400, -300, 900)
+	assert.Equal(t, len(filterRangeSearch(remaining, r)), tree.RangeCount(r))
+	assert.Equal(t, len(filterRadiusSearch(remaining, &Point2D{}, 700)), tree.RadiusCount(&Point2D{}, 700))
+	assert.Equal(t, len(remaining), tree.RangeCount(kdrange.New(-1500, 1500, -1500, 1500)))
+}
+
 // TestKDTree_RemoveAxisInversion is a targeted test for issue #6.
 //
 // https://github.com/kyroy/kdtree/issues/6
@@ -528,6 +902,7 @@ func TestKDTree_RemoveAxisInversion(t *testing.T) {
 	remove := &Point2D{X: 265, Y: 176}
 
 	tree.Remove(remove)
+	assert.NoError(t, tree.Validate())
 
 	fewNN := tree.KNN(search, 1)
 	manyNN := tree.KNN(search, 10)
@@ -565,6 +940,7 @@ func TestKDTree_RemoveAxisInversionGenerator(t *testing.T) {
 				arr = arr[:len(arr)-1]
 			}
 		}
+		assert.NoError(t, tree.Validate())
 	}
 }
 
@@ -618,6 +994,29 @@ func BenchmarkKNN(b *testing.B) {
 	}
 }
 
+func BenchmarkRangeSearch(b *testing.B) {
+	benchmarks := []struct {
+		name  string
+		input []kdtree.Point
+		r     kdrange.Range
+	}{
+		{name: "p:1000", input: generateTestCaseData(1000), r: kdrange.New(-500, 250, -250, 500)},
+		{name: "p:10000", input: generateTestCaseData(10000), r: kdrange.New(-500, 250, -250, 500)},
+		{name: "p:100000", input: generateTestCaseData(100000), r: kdrange.New(-500, 250, -250, 500)},
+	}
+	for _, bm := range benchmarks {
+		var res []kdtree.Point
+		tree := kdtree.New(bm.input)
+		b.Run(bm.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				res = tree.RangeSearch(bm.r)
+			}
+			resultPoints = res
+		})
+	}
+}
+
 // helpers
 
 func generateTestCaseData(size int) []kdtree.Point {
@@ -676,6 +1075,16 @@ pointLoop:
 	return result
 }
 
+func filterRadiusSearch(points []kdtree.Point, p kdtree.Point, radius float64) []kdtree.Point {
+	result := make([]kdtree.Point, 0)
+	for _, point := range points {
+		if distance(p, point) <= radius {
+			result = append(result, point)
+		}
+	}
+	return result
+}
+
 func distance(p1, p2 kdtree.Point) float64 {
 	sum := 0.
 	for i := 0; i < p1.Dimensions(); i++ {
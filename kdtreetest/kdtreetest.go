@@ -0,0 +1,260 @@
+/*
+ * Copyright 2020 Dennis Kuhnert
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+// Package kdtreetest provides a conformance test suite for the k-d tree.
+//
+// Implementations of kdtree.Point can run it to check that the tree returns the same results
+// as a brute force search with their type:
+//
+//    func TestMyPoint(t *testing.T) {
+//        kdtreetest.Run(t, 3, func(coordinates []float64) kdtree.Point {
+//            return &MyPoint{X: coordinates[0], Y: coordinates[1], Z: coordinates[2]}
+//        })
+//    }
+package kdtreetest
+
+import (
+	"fmt"
+	"github.com/kyroy/kdtree"
+	"github.com/kyroy/kdtree/kdrange"
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+// NamedSplitter is a kdtree.Splitter with a name for subtests.
+type NamedSplitter struct {
+	Name     string
+	Splitter kdtree.Splitter
+}
+
+// Splitters contains all splitters of the kdtree package, so tests can build a tree with each of them.
+var Splitters = []NamedSplitter{
+	{Name: "RoundRobin", Splitter: kdtree.RoundRobin},
+	{Name: "MaxSpread", Splitter: kdtree.MaxSpread},
+	{Name: "MaxVariance", Splitter: kdtree.MaxVariance},
+	{Name: "SlidingMidpoint", Splitter: kdtree.SlidingMidpoint},
+}
+
+// Run runs the conformance tests with points of the given dimensions created by newPoint.
+// The results of the tree are compared with BruteForceKNN and BruteForceRange for all splitters.
+func Run(t *testing.T, dims int, newPoint func(coordinates []float64) kdtree.Point) {
+	t.Run("Point", func(t *testing.T) {
+		coordinates := make([]float64, dims)
+		for i := range coordinates {
+			coordinates[i] = float64(i) + 0.5
+		}
+		p := newPoint(coordinates)
+		if p.Dimensions() != dims {
+			t.Fatalf("Dimensions() = %d, expected %d", p.Dimensions(), dims)
+		}
+		for i, c := range coordinates {
+			if p.Dimension(i) != c {
+				t.Errorf("Dimension(%d) = %v, expected %v", i, p.Dimension(i), c)
+			}
+		}
+	})
+
+	for _, s := range Splitters {
+		t.Run(s.Name, func(t *testing.T) {
+			r := rand.New(rand.NewSource(1))
+			for _, data := range []struct {
+				name  string
+				input []kdtree.Point
+			}{
+				{name: "uniform", input: generate(r, newPoint, dims, 500, 0)},
+				{name: "duplicates", input: generate(r, newPoint, dims, 500, 5)},
+			} {
+				t.Run(data.name, func(t *testing.T) {
+					tree := kdtree.New(append([]kdtree.Point(nil), data.input...), kdtree.WithSplitter(s.Splitter))
+					check(t, r, newPoint, tree, data.input)
+
+					// interleave inserts and removes
+					points := append([]kdtree.Point(nil), data.input[:250]...)
+					tree = kdtree.New(append([]kdtree.Point(nil), points...), kdtree.WithSplitter(s.Splitter))
+					for i, p := range data.input[250:] {
+						tree.Insert(p)
+						points = append(points, p)
+						if i%2 == 0 {
+							points = Remove(t, tree, points, points[r.Intn(len(points))])
+						}
+					}
+					check(t, r, newPoint, tree, points)
+
+					tree.Balance()
+					check(t, r, newPoint, tree, points)
+				})
+			}
+		})
+	}
+}
+
+// check compares the tree containing points with the brute force search.
+func check(t *testing.T, r *rand.Rand, newPoint func(coordinates []float64) kdtree.Point, tree *kdtree.KDTree, points []kdtree.Point) {
+	t.Helper()
+	if err := tree.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if tree.Len() != len(points) {
+		t.Fatalf("Len() = %d, expected %d", tree.Len(), len(points))
+	}
+	AssertSamePoints(t, points, tree.Points())
+
+	dims := points[0].Dimensions()
+	for i := 0; i < 10; i++ {
+		p := generate(r, newPoint, dims, 1, 0)[0]
+		for _, k := range []int{1, 5, len(points) + 1} {
+			AssertSameDistances(t, p, BruteForceKNN(points, p, k), tree.KNN(p, k))
+		}
+
+		limits := make([]float64, 0, 2*dims)
+		for dim := 0; dim < dims; dim++ {
+			a, b := r.Float64()*20-10, r.Float64()*20-10
+			limits = append(limits, math.Min(a, b), math.Max(a, b))
+		}
+		region := kdrange.New(limits...)
+		AssertSamePoints(t, BruteForceRange(points, region), tree.RangeSearch(region))
+		if tree.RangeCount(region) != len(BruteForceRange(points, region)) {
+			t.Errorf("RangeCount(%v) = %d, expected %d", region, tree.RangeCount(region), len(BruteForceRange(points, region)))
+		}
+	}
+}
+
+// Remove removes p from the tree and the first point with the same coordinates from points.
+// It fails the test if the tree does not return such a point.
+func Remove(t *testing.T, tree *kdtree.KDTree, points []kdtree.Point, p kdtree.Point) []kdtree.Point {
+	t.Helper()
+	removed := tree.Remove(p)
+	for i, o := range points {
+		if equal(o, p) {
+			if removed == nil || !equal(removed, p) {
+				t.Fatalf("Remove(%v) = %v, expected a point with the same coordinates", p, removed)
+			}
+			return append(points[:i:i], points[i+1:]...)
+		}
+	}
+	if removed != nil {
+		t.Fatalf("Remove(%v) = %v, expected nil", p, removed)
+	}
+	return points
+}
+
+// BruteForceKNN returns the k points nearest to p, sorted by their distance. Starting with the nearest.
+func BruteForceKNN(points []kdtree.Point, p kdtree.Point, k int) []kdtree.Point {
+	sorted := append([]kdtree.Point(nil), points...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return Distance(p, sorted[i]) < Distance(p, sorted[j])
+	})
+	if k < len(sorted) {
+		sorted = sorted[:k]
+	}
+	return sorted
+}
+
+// BruteForceRange returns all points in the region r.
+func BruteForceRange(points []kdtree.Point, r kdrange.Region) []kdtree.Point {
+	result := []kdtree.Point{}
+	for _, p := range points {
+		if r.Contains(p) {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+// Distance returns the euclidean distance between a and b.
+func Distance(a, b kdtree.Point) float64 {
+	sum := 0.
+	for i := 0; i < a.Dimensions(); i++ {
+		d := a.Dimension(i) - b.Dimension(i)
+		sum += d * d
+	}
+	return math.Sqrt(sum)
+}
+
+// AssertSameDistances fails the test if the points of actual do not have the same distances to p as the ones of expected.
+// Points with equal distances may be returned in any order.
+func AssertSameDistances(t *testing.T, p kdtree.Point, expected, actual []kdtree.Point) {
+	t.Helper()
+	if len(expected) != len(actual) {
+		t.Errorf("KNN(%v) returned %d points, expected %d", p, len(actual), len(expected))
+		return
+	}
+	for i := range expected {
+		e, a := Distance(p, expected[i]), Distance(p, actual[i])
+		if math.Abs(e-a) > 1e-9*math.Max(1, e) {
+			t.Errorf("KNN(%v)[%d] = %v with distance %v, expected distance %v", p, i, actual[i], a, e)
+			return
+		}
+	}
+}
+
+// AssertSamePoints fails the test if actual and expected do not contain the same coordinates, ignoring the order.
+func AssertSamePoints(t *testing.T, expected, actual []kdtree.Point) {
+	t.Helper()
+	e, a := sortedCoordinates(expected), sortedCoordinates(actual)
+	if len(e) != len(a) {
+		t.Errorf("got %d points, expected %d", len(a), len(e))
+		return
+	}
+	for i := range e {
+		if e[i] != a[i] {
+			t.Errorf("got point %s, expected %s", a[i], e[i])
+			return
+		}
+	}
+}
+
+func sortedCoordinates(points []kdtree.Point) []string {
+	result := make([]string, len(points))
+	for i, p := range points {
+		coordinates := make([]float64, p.Dimensions())
+		for dim := range coordinates {
+			// adding 0 turns -0 into 0, they are equal coordinates
+			coordinates[dim] = p.Dimension(dim) + 0
+		}
+		result[i] = fmt.Sprint(coordinates)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func equal(a, b kdtree.Point) bool {
+	for i := 0; i < a.Dimensions(); i++ {
+		if a.Dimension(i) != b.Dimension(i) {
+			return false
+		}
+	}
+	return true
+}
+
+// generate returns n random points in [-10, 10). If grid is positive, the coordinates are
+// rounded to multiples of 10/grid, which results in duplicated coordinates and points.
+func generate(r *rand.Rand, newPoint func(coordinates []float64) kdtree.Point, dims, n, grid int) []kdtree.Point {
+	points := make([]kdtree.Point, n)
+	for i := range points {
+		coordinates := make([]float64, dims)
+		for dim := range coordinates {
+			coordinates[dim] = r.Float64()*20 - 10
+			if grid > 0 {
+				coordinates[dim] = math.Round(coordinates[dim]*float64(grid)/10) * 10 / float64(grid)
+			}
+		}
+		points[i] = newPoint(coordinates)
+	}
+	return points
+}
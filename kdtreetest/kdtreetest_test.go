@@ -0,0 +1,75 @@
+/*
+ * Copyright 2020 Dennis Kuhnert
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package kdtreetest_test
+
+import (
+	"github.com/kyroy/kdtree"
+	"github.com/kyroy/kdtree/kdtreetest"
+	"github.com/kyroy/kdtree/points"
+	"testing"
+)
+
+// vector is a point implemented as a value type.
+type vector [2]float64
+
+func (v vector) Dimensions() int {
+	return len(v)
+}
+
+func (v vector) Dimension(i int) float64 {
+	return v[i]
+}
+
+func TestRun(t *testing.T) {
+	t.Run("Point2D", func(t *testing.T) {
+		kdtreetest.Run(t, 2, func(coordinates []float64) kdtree.Point {
+			return &points.Point2D{X: coordinates[0], Y: coordinates[1]}
+		})
+	})
+	t.Run("Point3D", func(t *testing.T) {
+		kdtreetest.Run(t, 3, func(coordinates []float64) kdtree.Point {
+			return &points.Point3D{X: coordinates[0], Y: coordinates[1], Z: coordinates[2]}
+		})
+	})
+	t.Run("Point", func(t *testing.T) {
+		kdtreetest.Run(t, 5, func(coordinates []float64) kdtree.Point {
+			return points.NewPoint(coordinates, nil)
+		})
+	})
+	t.Run("value type", func(t *testing.T) {
+		kdtreetest.Run(t, 2, func(coordinates []float64) kdtree.Point {
+			return vector{coordinates[0], coordinates[1]}
+		})
+	})
+}
+
+func TestBruteForceKNN(t *testing.T) {
+	input := []kdtree.Point{vector{0, 0}, vector{3, 0}, vector{1, 0}, vector{2, 0}}
+	result := kdtreetest.BruteForceKNN(input, vector{2.1, 0}, 3)
+	expected := []kdtree.Point{vector{2, 0}, vector{3, 0}, vector{1, 0}}
+	if len(result) != len(expected) {
+		t.Fatalf("got %v, expected %v", result, expected)
+	}
+	for i := range expected {
+		if result[i] != expected[i] {
+			t.Fatalf("got %v, expected %v", result, expected)
+		}
+	}
+	if len(kdtreetest.BruteForceKNN(input, vector{}, 10)) != 4 {
+		t.Errorf("expected all points for k > len(points)")
+	}
+}
@@ -0,0 +1,299 @@
+/*
+ * Copyright 2020 Dennis Kuhnert
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+// Package kmeans implements k-means clustering on top of the k-d tree.
+//
+// Every iteration uses the filtering algorithm of Kanungo et al.: the candidate centroids are
+// filtered while descending the tree, and subtrees with a single remaining candidate are assigned
+// as a whole using the cached subtree sums and sizes.
+package kmeans
+
+import (
+	"github.com/kyroy/kdtree"
+	"github.com/kyroy/kdtree/kdrange"
+	"math"
+	"math/rand"
+)
+
+// Result contains the outcome of Cluster.
+type Result struct {
+	// Centroids contains the coordinates of the k centroids.
+	Centroids [][]float64
+	// Counts contains the number of points assigned to each centroid.
+	Counts []int
+	// Iterations is the number of performed iterations.
+	Iterations int
+	// Converged is set if no centroid moved more than the tolerance in the last iteration.
+	Converged bool
+}
+
+// Nearest returns the index of the centroid nearest to p.
+func (r *Result) Nearest(p kdtree.Point) int {
+	return nearest(r.Centroids, allCandidates(len(r.Centroids)), func(dim int) float64 {
+		return p.Dimension(dim)
+	})
+}
+
+// Option configures Cluster.
+type Option func(*config)
+
+type config struct {
+	maxIterations int
+	tolerance     float64
+	seed          int64
+	centroids     [][]float64
+}
+
+// WithMaxIterations limits the number of iterations. The default is 100.
+func WithMaxIterations(n int) Option {
+	return func(c *config) {
+		c.maxIterations = n
+	}
+}
+
+// WithTolerance stops the iterations once no centroid moves more than tolerance. The default is 0.
+func WithTolerance(tolerance float64) Option {
+	return func(c *config) {
+		c.tolerance = tolerance
+	}
+}
+
+// WithSeed sets the seed of the k-means++ initialization. The same seed and tree result in the same clustering.
+// The default is 1.
+func WithSeed(seed int64) Option {
+	return func(c *config) {
+		c.seed = seed
+	}
+}
+
+// WithInitialCentroids skips the k-means++ initialization and starts with the given centroids.
+// k is ignored and the number of centroids is used instead.
+func WithInitialCentroids(centroids [][]float64) Option {
+	return func(c *config) {
+		c.centroids = centroids
+	}
+}
+
+// Cluster partitions the points of the tree into k clusters.
+// The centroids are initialized with k-means++ unless WithInitialCentroids is given.
+//
+// Returns nil if the tree is empty, k is not positive or an initial centroid differs from the points in its dimensions.
+// k is reduced to the number of points of the tree.
+func Cluster(tree *kdtree.KDTree, k int, options ...Option) *Result {
+	c := &config{maxIterations: 100, seed: 1}
+	for _, option := range options {
+		option(c)
+	}
+
+	root := tree.Root()
+	if root.IsEmpty() {
+		return nil
+	}
+	centroids := c.centroids
+	if centroids == nil {
+		if k <= 0 {
+			return nil
+		}
+		if k > root.Len() {
+			k = root.Len()
+		}
+		centroids = initPlusPlus(tree.Points(), k, rand.New(rand.NewSource(c.seed)))
+	} else {
+		centroids = copyCentroids(centroids)
+	}
+	if len(centroids) == 0 {
+		return nil
+	}
+	dims := root.Point().Dimensions()
+	for _, centroid := range centroids {
+		if len(centroid) != dims {
+			return nil
+		}
+	}
+
+	result := &Result{Centroids: centroids}
+	for result.Iterations < c.maxIterations && !result.Converged {
+		f := &filter{
+			centroids: result.Centroids,
+			sums:      make([][]float64, len(result.Centroids)),
+			counts:    make([]int, len(result.Centroids)),
+		}
+		for i := range f.sums {
+			f.sums[i] = make([]float64, dims)
+		}
+		f.filter(root, allCandidates(len(result.Centroids)))
+
+		result.Converged = true
+		next := make([][]float64, len(result.Centroids))
+		for i, centroid := range result.Centroids {
+			if f.counts[i] == 0 {
+				// keep empty clusters in place
+				next[i] = centroid
+				continue
+			}
+			next[i] = make([]float64, dims)
+			for dim := range next[i] {
+				next[i][dim] = f.sums[i][dim] / float64(f.counts[i])
+			}
+			if math.Sqrt(squaredDistance(centroid, next[i])) > c.tolerance {
+				result.Converged = false
+			}
+		}
+		result.Centroids = next
+		result.Counts = f.counts
+		result.Iterations++
+	}
+	return result
+}
+
+// filter accumulates the points assigned to every centroid.
+type filter struct {
+	centroids [][]float64
+	sums      [][]float64
+	counts    []int
+}
+
+// filter assigns all points of the cell to the nearest of the candidate centroids.
+func (f *filter) filter(c kdtree.Cell, candidates []int) {
+	if c.IsEmpty() {
+		return
+	}
+
+	bounds := c.Bounds()
+	center := bounds.Center()
+	best := nearest(f.centroids, candidates, func(dim int) float64 {
+		return center[dim]
+	})
+	remaining := make([]int, 0, len(candidates))
+	for _, z := range candidates {
+		if z == best || !farther(f.centroids[z], f.centroids[best], bounds) {
+			remaining = append(remaining, z)
+		}
+	}
+
+	if len(remaining) == 1 {
+		// the whole subtree belongs to best
+		for dim, v := range c.Sum() {
+			f.sums[best][dim] += v
+		}
+		f.counts[best] += c.Len()
+		return
+	}
+
+	p := c.Point()
+	z := nearest(f.centroids, remaining, p.Dimension)
+	for dim := range f.sums[z] {
+		f.sums[z][dim] += p.Dimension(dim)
+	}
+	f.counts[z]++
+	f.filter(c.Left(), remaining)
+	f.filter(c.Right(), remaining)
+}
+
+// farther reports whether the centroid z is farther than best from every point of the box b.
+// It suffices to check the corner of b in the direction from best to z.
+func farther(z, best []float64, b kdrange.Range) bool {
+	zDistance, bestDistance := 0., 0.
+	for dim, limit := range b {
+		v := limit[0]
+		if z[dim] > best[dim] {
+			v = limit[1]
+		}
+		zDistance += (z[dim] - v) * (z[dim] - v)
+		bestDistance += (best[dim] - v) * (best[dim] - v)
+	}
+	return zDistance >= bestDistance
+}
+
+// nearest returns the candidate centroid nearest to the point with the given coordinates.
+func nearest(centroids [][]float64, candidates []int, coordinate func(dim int) float64) int {
+	best, bestDistance := -1, math.Inf(1)
+	for _, z := range candidates {
+		d := 0.
+		for dim, v := range centroids[z] {
+			d += (v - coordinate(dim)) * (v - coordinate(dim))
+		}
+		if d < bestDistance {
+			best, bestDistance = z, d
+		}
+	}
+	return best
+}
+
+// initPlusPlus chooses k centroids from the points with the k-means++ seeding.
+// Every next centroid is chosen with a probability proportional to its squared distance to the nearest chosen one.
+func initPlusPlus(points []kdtree.Point, k int, r *rand.Rand) [][]float64 {
+	centroids := [][]float64{coordinates(points[r.Intn(len(points))])}
+	distances := make([]float64, len(points))
+	for i := range distances {
+		distances[i] = math.Inf(1)
+	}
+
+	for len(centroids) < k {
+		last := centroids[len(centroids)-1]
+		sum := 0.
+		for i, p := range points {
+			distances[i] = math.Min(distances[i], squaredDistance(last, coordinates(p)))
+			sum += distances[i]
+		}
+
+		next := r.Intn(len(points))
+		if sum > 0 {
+			target := r.Float64() * sum
+			for i, d := range distances {
+				target -= d
+				if target < 0 {
+					next = i
+					break
+				}
+			}
+		}
+		centroids = append(centroids, coordinates(points[next]))
+	}
+	return centroids
+}
+
+func allCandidates(k int) []int {
+	candidates := make([]int, k)
+	for i := range candidates {
+		candidates[i] = i
+	}
+	return candidates
+}
+
+func coordinates(p kdtree.Point) []float64 {
+	c := make([]float64, p.Dimensions())
+	for dim := range c {
+		c[dim] = p.Dimension(dim)
+	}
+	return c
+}
+
+func copyCentroids(centroids [][]float64) [][]float64 {
+	c := make([][]float64, len(centroids))
+	for i := range centroids {
+		c[i] = append([]float64(nil), centroids[i]...)
+	}
+	return c
+}
+
+func squaredDistance(a, b []float64) float64 {
+	sum := 0.
+	for dim := range a {
+		sum += (a[dim] - b[dim]) * (a[dim] - b[dim])
+	}
+	return sum
+}
@@ -0,0 +1,173 @@
+/*
+ * Copyright 2020 Dennis Kuhnert
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package kmeans_test
+
+import (
+	"github.com/kyroy/kdtree"
+	"github.com/kyroy/kdtree/kmeans"
+	. "github.com/kyroy/kdtree/points"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestCluster(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     []kdtree.Point
+		k         int
+		centroids [][]float64
+		counts    []int
+	}{
+		{name: "nil", input: nil, k: 2, centroids: nil},
+		{name: "k 0", input: []kdtree.Point{&Point2D{X: 1, Y: 1}}, k: 0, centroids: nil},
+		{
+			name:      "single point",
+			input:     []kdtree.Point{&Point2D{X: 1, Y: 2}},
+			k:         3,
+			centroids: [][]float64{{1, 2}},
+			counts:    []int{1},
+		},
+		{
+			name: "two groups",
+			input: []kdtree.Point{
+				&Point2D{X: 0, Y: 0}, &Point2D{X: 2, Y: 0}, &Point2D{X: 1, Y: 3},
+				&Point2D{X: 100, Y: 100}, &Point2D{X: 102, Y: 100},
+			},
+			k:         2,
+			centroids: [][]float64{{1, 1}, {101, 100}},
+			counts:    []int{3, 2},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := kmeans.Cluster(kdtree.New(test.input), test.k)
+			if test.centroids == nil {
+				assert.Nil(t, result)
+				return
+			}
+			assert.True(t, result.Converged)
+			// the order of the centroids depends on the seeding
+			for i, centroid := range test.centroids {
+				z := result.Nearest(NewPoint(centroid, nil))
+				assert.Equal(t, centroid, result.Centroids[z])
+				assert.Equal(t, test.counts[i], result.Counts[z])
+			}
+		})
+	}
+}
+
+func TestClusterWithInitialCentroids(t *testing.T) {
+	input := []kdtree.Point{
+		&Point2D{X: 0, Y: 0}, &Point2D{X: 2, Y: 0},
+		&Point2D{X: 10, Y: 0}, &Point2D{X: 12, Y: 0},
+	}
+	initial := [][]float64{{-5, 0}, {20, 0}, {1000, 1000}}
+	result := kmeans.Cluster(kdtree.New(input), 0, kmeans.WithInitialCentroids(initial))
+	assert.Equal(t, [][]float64{{1, 0}, {11, 0}, {1000, 1000}}, result.Centroids)
+	assert.Equal(t, []int{2, 2, 0}, result.Counts)
+	assert.Equal(t, [][]float64{{-5, 0}, {20, 0}, {1000, 1000}}, initial)
+}
+
+func TestClusterWithInvalidInitialCentroids(t *testing.T) {
+	tree := kdtree.New([]kdtree.Point{&Point2D{X: 0, Y: 0}, &Point2D{X: 2, Y: 0}})
+	assert.Nil(t, kmeans.Cluster(tree, 0, kmeans.WithInitialCentroids([][]float64{{0, 0}, {1}})))
+	assert.Nil(t, kmeans.Cluster(tree, 0, kmeans.WithInitialCentroids([][]float64{{0, 0, 0}})))
+}
+
+func TestClusterWithMaxIterations(t *testing.T) {
+	tree := kdtree.New(generateBlobs(4, 100, 1000))
+	result := kmeans.Cluster(tree, 8, kmeans.WithMaxIterations(1))
+	assert.Equal(t, 1, result.Iterations)
+	assert.False(t, result.Converged)
+}
+
+func TestClusterDeterministic(t *testing.T) {
+	tree := kdtree.New(generateBlobs(5, 200, 1000))
+	a := kmeans.Cluster(tree, 5, kmeans.WithSeed(42))
+	b := kmeans.Cluster(tree, 5, kmeans.WithSeed(42))
+	assert.Equal(t, a, b)
+}
+
+func TestClusterWithGenerator(t *testing.T) {
+	input := generateBlobs(6, 300, 1000)
+	tree := kdtree.New(input)
+	for _, k := range []int{1, 3, 6, 10} {
+		initial := kmeans.Cluster(tree, k, kmeans.WithMaxIterations(0), kmeans.WithSeed(7)).Centroids
+		result := kmeans.Cluster(tree, k, kmeans.WithInitialCentroids(initial), kmeans.WithMaxIterations(5))
+		centroids, counts := lloyd(input, initial, 5)
+		assert.Equal(t, counts, result.Counts)
+		for i := range centroids {
+			assert.InDeltaSlice(t, centroids[i], result.Centroids[i], 1e-6)
+		}
+	}
+}
+
+func BenchmarkCluster(b *testing.B) {
+	tree := kdtree.New(generateBlobs(10, 10000, 10000))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		kmeans.Cluster(tree, 10)
+	}
+}
+
+// lloyd runs the given number of iterations of the naive k-means algorithm.
+func lloyd(input []kdtree.Point, centroids [][]float64, iterations int) ([][]float64, []int) {
+	var counts []int
+	for ; iterations > 0; iterations-- {
+		sums := make([][]float64, len(centroids))
+		counts = make([]int, len(centroids))
+		for _, p := range input {
+			best, bestDistance := 0, math.Inf(1)
+			for i, c := range centroids {
+				if d := math.Pow(p.Dimension(0)-c[0], 2) + math.Pow(p.Dimension(1)-c[1], 2); d < bestDistance {
+					best, bestDistance = i, d
+				}
+			}
+			if sums[best] == nil {
+				sums[best] = make([]float64, 2)
+			}
+			sums[best][0] += p.Dimension(0)
+			sums[best][1] += p.Dimension(1)
+			counts[best]++
+		}
+		next := make([][]float64, len(centroids))
+		for i := range centroids {
+			if counts[i] == 0 {
+				next[i] = centroids[i]
+				continue
+			}
+			next[i] = []float64{sums[i][0] / float64(counts[i]), sums[i][1] / float64(counts[i])}
+		}
+		centroids = next
+	}
+	return centroids, counts
+}
+
+// generateBlobs returns clusters of n points each, normally distributed around random centers.
+func generateBlobs(clusters, n int, size float64) []kdtree.Point {
+	r := rand.New(rand.NewSource(1))
+	var result []kdtree.Point
+	for c := 0; c < clusters; c++ {
+		x, y := r.Float64()*size, r.Float64()*size
+		for i := 0; i < n; i++ {
+			result = append(result, &Point2D{X: x + r.NormFloat64()*size/50, Y: y + r.NormFloat64()*size/50})
+		}
+	}
+	return result
+}
@@ -0,0 +1,200 @@
+/*
+ * Copyright 2020 Dennis Kuhnert
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package kdtree
+
+import (
+	"github.com/kyroy/kdtree/kdrange"
+	"github.com/kyroy/priority-queue"
+	"math"
+	"sort"
+)
+
+// KNNSegment returns the k-nearest neighbours of the line segment from a to b.
+// The points are sorted by their distance to the segment. Starting with the nearest.
+//
+// Returns an empty slice when a or b is nil or their dimensions do not match the tree's dimensions.
+func (t *KDTree) KNNSegment(a, b Point, k int) []Point {
+	return t.knnLine(a, b, 1, k)
+}
+
+// KNNRay returns the k-nearest neighbours of the ray starting at origin and passing through the point through.
+// The points are sorted by their distance to the ray. Starting with the nearest.
+//
+// Returns an empty slice when origin or through is nil or their dimensions do not match the tree's dimensions.
+func (t *KDTree) KNNRay(origin, through Point, k int) []Point {
+	return t.knnLine(origin, through, math.Inf(1), k)
+}
+
+func (t *KDTree) knnLine(a, b Point, maxT float64, k int) []Point {
+	if t.root == nil || a == nil || b == nil || k <= 0 ||
+		a.Dimensions() != t.root.Dimensions() || b.Dimensions() != t.root.Dimensions() {
+		return []Point{}
+	}
+
+	l := newLine(a, b, maxT)
+	nearestPQ := pq.NewPriorityQueue(pq.WithMinPrioSize(k))
+	knnLine(l, k, t.root, l.boxDistance(t.root.Bounds), nearestPQ)
+
+	return popPoints(nearestPQ, k)
+}
+
+// knnLine searches the subtree of n whose bounding box has the distance boxDistance to l.
+func knnLine(l *line, k int, n *node, boxDistance float64, nearestPQ *pq.PriorityQueue) {
+	if boxDistance > getKthOrLastDistance(nearestPQ, k-1) {
+		return
+	}
+
+	if d := l.distance(n); d < getKthOrLastDistance(nearestPQ, k-1) {
+		nearestPQ.Insert(n, d)
+	}
+
+	// visit the nearer child first to shrink the k-th distance early
+	first, second := n.Left, n.Right
+	firstDistance, secondDistance := math.Inf(1), math.Inf(1)
+	if first != nil {
+		firstDistance = l.boxDistance(first.Bounds)
+	}
+	if second != nil {
+		secondDistance = l.boxDistance(second.Bounds)
+	}
+	if secondDistance < firstDistance {
+		first, second = second, first
+		firstDistance, secondDistance = secondDistance, firstDistance
+	}
+	if first != nil {
+		knnLine(l, k, first, firstDistance, nearestPQ)
+	}
+	if second != nil {
+		knnLine(l, k, second, secondDistance, nearestPQ)
+	}
+}
+
+// line contains all points origin + t*direction with 0 <= t <= maxT.
+// It is a segment for a finite maxT and a ray for maxT = +Inf.
+type line struct {
+	origin    []float64
+	direction []float64
+	maxT      float64
+}
+
+func newLine(a, b Point, maxT float64) *line {
+	l := &line{
+		origin:    make([]float64, a.Dimensions()),
+		direction: make([]float64, a.Dimensions()),
+		maxT:      maxT,
+	}
+	for i := range l.origin {
+		l.origin[i] = a.Dimension(i)
+		l.direction[i] = b.Dimension(i) - a.Dimension(i)
+	}
+	return l
+}
+
+// distance returns the distance from p to the nearest point of the line.
+func (l *line) distance(p Point) float64 {
+	dd, pd := 0., 0.
+	for i, o := range l.origin {
+		dd += l.direction[i] * l.direction[i]
+		pd += (p.Dimension(i) - o) * l.direction[i]
+	}
+	t := 0.
+	if dd > 0 {
+		t = math.Max(0, math.Min(pd/dd, l.maxT))
+	}
+
+	sum := 0.
+	for i, o := range l.origin {
+		d := p.Dimension(i) - (o + t*l.direction[i])
+		sum += d * d
+	}
+	return math.Sqrt(sum)
+}
+
+// boxDistance returns the distance from the box b to the nearest point of the line.
+//
+// The line crosses the limits of b at a finite number of positions t. Between two of them,
+// every dimension stays either below, inside or above its limits and the squared distance
+// is a quadratic function of t that can be minimized exactly.
+func (l *line) boxDistance(b kdrange.Range) float64 {
+	ts := []float64{0}
+	if !math.IsInf(l.maxT, 1) {
+		ts = append(ts, l.maxT)
+	}
+	for dim, limit := range b {
+		d := l.direction[dim]
+		if d == 0 {
+			continue
+		}
+		for _, v := range limit {
+			if t := (v - l.origin[dim]) / d; t > 0 && t < l.maxT {
+				ts = append(ts, t)
+			}
+		}
+	}
+	sort.Float64s(ts)
+
+	min := math.Inf(1)
+	for i, t0 := range ts {
+		min = math.Min(min, l.boxSquaredDistanceAt(t0, b))
+
+		t1 := l.maxT
+		if i+1 < len(ts) {
+			t1 = ts[i+1]
+		}
+		mid := t0 + 1
+		if !math.IsInf(t1, 1) {
+			mid = (t0 + t1) / 2
+		}
+
+		// f(t) = sum (origin + t*direction - limit)^2 over all dimensions outside of their limits
+		qa, qb := 0., 0.
+		for dim, limit := range b {
+			v := l.origin[dim] + mid*l.direction[dim]
+			var c float64
+			switch {
+			case v < limit[0]:
+				c = limit[0]
+			case v > limit[1]:
+				c = limit[1]
+			default:
+				continue
+			}
+			qa += l.direction[dim] * l.direction[dim]
+			qb += l.direction[dim] * (l.origin[dim] - c)
+		}
+		if qa > 0 {
+			if t := -qb / qa; t > t0 && t < t1 {
+				min = math.Min(min, l.boxSquaredDistanceAt(t, b))
+			}
+		}
+	}
+	return math.Sqrt(min)
+}
+
+// boxSquaredDistanceAt returns the squared distance from the box b to the point of the line at t.
+func (l *line) boxSquaredDistanceAt(t float64, b kdrange.Range) float64 {
+	sum := 0.
+	for dim, limit := range b {
+		v := l.origin[dim] + t*l.direction[dim]
+		if v < limit[0] {
+			sum += (limit[0] - v) * (limit[0] - v)
+		} else if v > limit[1] {
+			sum += (v - limit[1]) * (v - limit[1])
+		}
+	}
+	return sum
+}
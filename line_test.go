@@ -0,0 +1,146 @@
+/*
+ * Copyright 2020 Dennis Kuhnert
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package kdtree_test
+
+import (
+	"github.com/kyroy/kdtree"
+	. "github.com/kyroy/kdtree/points"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"sort"
+	"testing"
+)
+
+func TestKDTree_KNNSegment(t *testing.T) {
+	tests := []struct {
+		name   string
+		a, b   kdtree.Point
+		k      int
+		input  []kdtree.Point
+		output []kdtree.Point
+	}{
+		{
+			name:   "nil",
+			a:      nil,
+			b:      &Point2D{X: 1, Y: 1},
+			k:      3,
+			input:  []kdtree.Point{&Point2D{X: 1., Y: 2.}},
+			output: []kdtree.Point{},
+		},
+		{
+			name:   "wrong dim",
+			a:      &Point3D{},
+			b:      &Point3D{X: 1},
+			k:      3,
+			input:  []kdtree.Point{&Point2D{X: 1., Y: 2.}},
+			output: []kdtree.Point{},
+		},
+		{
+			name:   "empty",
+			a:      &Point2D{},
+			b:      &Point2D{X: 1},
+			k:      3,
+			input:  []kdtree.Point{},
+			output: []kdtree.Point{},
+		},
+		{
+			name:   "small 2D example",
+			a:      &Point2D{X: 0, Y: 0},
+			b:      &Point2D{X: 10, Y: 0},
+			k:      3,
+			input:  []kdtree.Point{&Point2D{X: 1, Y: 3}, &Point2D{X: 1, Y: 8}, &Point2D{X: 2, Y: 2}, &Point2D{X: 2, Y: 10}, &Point2D{X: 3, Y: 6}, &Point2D{X: 4, Y: 1.5}, &Point2D{X: 5, Y: 4}, &Point2D{X: 6, Y: 8}, &Point2D{X: 7, Y: 4}, &Point2D{X: 13, Y: 0.5}, &Point2D{X: 8, Y: 2.5}},
+			output: []kdtree.Point{&Point2D{X: 4, Y: 1.5}, &Point2D{X: 2, Y: 2}, &Point2D{X: 8, Y: 2.5}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tree := kdtree.New(test.input)
+			assert.Equal(t, test.output, tree.KNNSegment(test.a, test.b, test.k))
+		})
+	}
+}
+
+func TestKDTree_KNNRay(t *testing.T) {
+	input := []kdtree.Point{&Point2D{X: -3, Y: 0.1}, &Point2D{X: 1, Y: 3}, &Point2D{X: 2, Y: 2}, &Point2D{X: 20, Y: 0.5}, &Point2D{X: 8, Y: 2.5}}
+	tree := kdtree.New(input)
+	assert.Equal(t, []kdtree.Point{&Point2D{X: 20, Y: 0.5}, &Point2D{X: 2, Y: 2}}, tree.KNNRay(&Point2D{X: 0, Y: 0}, &Point2D{X: 1, Y: 0}, 2))
+	assert.Equal(t, []kdtree.Point{&Point2D{X: -3, Y: 0.1}, &Point2D{X: 2, Y: 2}}, tree.KNNRay(&Point2D{X: 0, Y: 0}, &Point2D{X: -1, Y: 0}, 2))
+}
+
+func TestKDTree_KNNLineWithGenerator(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b kdtree.Point
+		maxT float64
+		k    int
+		dims int
+		size int
+	}{
+		{name: "segment 2D", a: &Point2D{X: -400, Y: 100}, b: &Point2D{X: 900, Y: -300}, maxT: 1, k: 10, dims: 2, size: 10000},
+		{name: "point segment 2D", a: &Point2D{X: 100, Y: 100}, b: &Point2D{X: 100, Y: 100}, maxT: 1, k: 10, dims: 2, size: 10000},
+		{name: "ray 2D", a: &Point2D{X: -400, Y: 100}, b: &Point2D{X: 900, Y: -300}, maxT: math.Inf(1), k: 10, dims: 2, size: 10000},
+		{name: "segment 3D", a: NewPoint([]float64{-400, 100, 0}, nil), b: NewPoint([]float64{900, -300, 700}, nil), maxT: 1, k: 20, dims: 3, size: 5000},
+		{name: "ray 4D", a: NewPoint([]float64{-400, 100, 0, 0}, nil), b: NewPoint([]float64{900, -300, 700, 10}, nil), maxT: math.Inf(1), k: 20, dims: 4, size: 5000},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			input := make([]kdtree.Point, 0, test.size)
+			for i := 0; i < test.size; i++ {
+				input = append(input, generateTestPoint(test.dims))
+			}
+			tree := kdtree.New(append([]kdtree.Point(nil), input...))
+
+			var result []kdtree.Point
+			if math.IsInf(test.maxT, 1) {
+				result = tree.KNNRay(test.a, test.b, test.k)
+			} else {
+				result = tree.KNNSegment(test.a, test.b, test.k)
+			}
+
+			expected := make([]float64, 0, len(input))
+			for _, p := range input {
+				expected = append(expected, lineDistance(test.a, test.b, test.maxT, p))
+			}
+			sort.Float64s(expected)
+			actual := make([]float64, 0, len(result))
+			for _, p := range result {
+				actual = append(actual, lineDistance(test.a, test.b, test.maxT, p))
+			}
+			assert.InDeltaSlice(t, expected[:test.k], actual, 1e-9)
+		})
+	}
+}
+
+// lineDistance computes the distance from p to the segment (maxT = 1) or ray (maxT = +Inf) from a through b.
+func lineDistance(a, b kdtree.Point, maxT float64, p kdtree.Point) float64 {
+	dd, pd := 0., 0.
+	for i := 0; i < a.Dimensions(); i++ {
+		d := b.Dimension(i) - a.Dimension(i)
+		dd += d * d
+		pd += d * (p.Dimension(i) - a.Dimension(i))
+	}
+	t := 0.
+	if dd > 0 {
+		t = math.Max(0, math.Min(pd/dd, maxT))
+	}
+	sum := 0.
+	for i := 0; i < a.Dimensions(); i++ {
+		d := p.Dimension(i) - (a.Dimension(i) + t*(b.Dimension(i)-a.Dimension(i)))
+		sum += d * d
+	}
+	return math.Sqrt(sum)
+}
@@ -0,0 +1,172 @@
+/*
+ * Copyright 2020 Dennis Kuhnert
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package kdtree
+
+import (
+	"github.com/kyroy/kdtree/kdrange"
+	"math"
+	"sort"
+)
+
+// Pair represents two points and the distance between them.
+type Pair struct {
+	A        Point
+	B        Point
+	Distance float64
+}
+
+// ClosestPair returns the two points of the tree with the smallest distance.
+//
+// Returns nil when the tree contains less than two points.
+func (t *KDTree) ClosestPair() *Pair {
+	if t.root == nil || t.root.Size < 2 {
+		return nil
+	}
+
+	var best *Pair
+	s := &pairSearch{sqBound: math.Inf(1)}
+	s.visit = func(a, b *node, sqDistance float64) {
+		if sqDistance < s.sqBound {
+			s.sqBound = sqDistance
+			best = &Pair{A: a.Point, B: b.Point, Distance: math.Sqrt(sqDistance)}
+		}
+	}
+	s.self(t.root)
+	return best
+}
+
+// PairsWithin returns all pairs of points of the tree with a distance of at most d.
+// Every pair is returned once, sorted by the distance. Starting with the closest.
+//
+// Returns an empty slice when d is negative.
+func (t *KDTree) PairsWithin(d float64) []Pair {
+	pairs := []Pair{}
+	if t.root == nil || d < 0 {
+		return pairs
+	}
+
+	s := &pairSearch{sqBound: d * d}
+	s.visit = func(a, b *node, sqDistance float64) {
+		pairs = append(pairs, Pair{A: a.Point, B: b.Point, Distance: math.Sqrt(sqDistance)})
+	}
+	s.self(t.root)
+
+	sort.Slice(pairs, func(i, j int) bool {
+		return pairs[i].Distance < pairs[j].Distance
+	})
+	return pairs
+}
+
+// pairSearch traverses pairs of subtrees and calls visit for all pairs of nodes
+// with a squared distance of at most sqBound. visit may lower sqBound.
+type pairSearch struct {
+	sqBound float64
+	visit   func(a, b *node, sqDistance float64)
+}
+
+// self searches all pairs within the subtree n.
+func (s *pairSearch) self(n *node) {
+	// search the smaller subproblems first, they tighten the bound for the closest pair
+	if n.Left != nil {
+		s.self(n.Left)
+	}
+	if n.Right != nil {
+		s.self(n.Right)
+	}
+	if n.Left != nil {
+		s.point(n, n.Left, false)
+	}
+	if n.Right != nil {
+		s.point(n, n.Right, false)
+	}
+	if n.Left != nil && n.Right != nil {
+		s.cross(n.Left, n.Right)
+	}
+}
+
+// point searches all pairs of p with a node of the subtree n.
+// If swapped is set, p is passed as the second node to visit.
+func (s *pairSearch) point(p, n *node, swapped bool) {
+	if minSquaredDistance(p, n.Bounds) > s.sqBound {
+		return
+	}
+
+	if d := squaredDistance(p, n); d <= s.sqBound {
+		if swapped {
+			s.visit(n, p, d)
+		} else {
+			s.visit(p, n, d)
+		}
+	}
+	if n.Left != nil {
+		s.point(p, n.Left, swapped)
+	}
+	if n.Right != nil {
+		s.point(p, n.Right, swapped)
+	}
+}
+
+// cross searches all pairs with one node of the subtree a and one of the disjoint subtree b.
+// Nodes of a are always passed as the first node to visit.
+func (s *pairSearch) cross(a, b *node) {
+	if boxSquaredDistance(a.Bounds, b.Bounds) > s.sqBound {
+		return
+	}
+
+	// split the larger subtree
+	if a.Size >= b.Size {
+		s.point(a, b, false)
+		first, second := nearerChild(a, b)
+		if first != nil {
+			s.cross(first, b)
+		}
+		if second != nil {
+			s.cross(second, b)
+		}
+	} else {
+		s.point(b, a, true)
+		first, second := nearerChild(b, a)
+		if first != nil {
+			s.cross(a, first)
+		}
+		if second != nil {
+			s.cross(a, second)
+		}
+	}
+}
+
+// nearerChild returns the children of n, starting with the one whose bounding box is nearer to the one of o.
+func nearerChild(n, o *node) (*node, *node) {
+	first, second := n.Left, n.Right
+	if first == nil || (second != nil && boxSquaredDistance(second.Bounds, o.Bounds) < boxSquaredDistance(first.Bounds, o.Bounds)) {
+		first, second = second, first
+	}
+	return first, second
+}
+
+// boxSquaredDistance returns the squared distance between the nearest points of the boxes a and b.
+func boxSquaredDistance(a, b kdrange.Range) float64 {
+	sum := 0.
+	for dim, limit := range a {
+		if limit[1] < b[dim][0] {
+			sum += (b[dim][0] - limit[1]) * (b[dim][0] - limit[1])
+		} else if b[dim][1] < limit[0] {
+			sum += (limit[0] - b[dim][1]) * (limit[0] - b[dim][1])
+		}
+	}
+	return sum
+}
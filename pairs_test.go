@@ -0,0 +1,123 @@
+/*
+ * Copyright 2020 Dennis Kuhnert
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package kdtree_test
+
+import (
+	"github.com/kyroy/kdtree"
+	. "github.com/kyroy/kdtree/points"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+func TestKDTree_ClosestPair(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  []kdtree.Point
+		output *kdtree.Pair
+	}{
+		{name: "empty", input: nil, output: nil},
+		{name: "1", input: []kdtree.Point{&Point2D{X: 1, Y: 2}}, output: nil},
+		{
+			name:   "2",
+			input:  []kdtree.Point{&Point2D{X: 1, Y: 2}, &Point2D{X: 4, Y: 6}},
+			output: &kdtree.Pair{A: &Point2D{X: 4, Y: 6}, B: &Point2D{X: 1, Y: 2}, Distance: 5},
+		},
+		{
+			name:   "small 2D example",
+			input:  []kdtree.Point{&Point2D{X: 1, Y: 3}, &Point2D{X: 1, Y: 8}, &Point2D{X: 2, Y: 2}, &Point2D{X: 2, Y: 10}, &Point2D{X: 3, Y: 6}, &Point2D{X: 4, Y: 1}, &Point2D{X: 5, Y: 4}, &Point2D{X: 6, Y: 8}, &Point2D{X: 7, Y: 4}, &Point2D{X: 7, Y: 7}, &Point2D{X: 8, Y: 2}, &Point2D{X: 8, Y: 4.5}, &Point2D{X: 9, Y: 9}},
+			output: &kdtree.Pair{A: &Point2D{X: 7, Y: 4}, B: &Point2D{X: 8, Y: 4.5}, Distance: math.Sqrt(1.25)},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pair := kdtree.New(test.input).ClosestPair()
+			if test.output == nil {
+				assert.Nil(t, pair)
+				return
+			}
+			assert.Equal(t, test.output.Distance, pair.Distance)
+			assert.ElementsMatch(t, []kdtree.Point{test.output.A, test.output.B}, []kdtree.Point{pair.A, pair.B})
+		})
+	}
+}
+
+func TestKDTree_ClosestPairWithGenerator(t *testing.T) {
+	for _, size := range []int{2, 10, 100, 1000, 3000} {
+		input := generateTestCaseData(size)
+		pair := kdtree.New(append([]kdtree.Point(nil), input...)).ClosestPair()
+
+		expected := math.Inf(1)
+		for i := range input {
+			for j := i + 1; j < len(input); j++ {
+				expected = math.Min(expected, distance(input[i], input[j]))
+			}
+		}
+		assert.Equal(t, expected, pair.Distance)
+		assert.Equal(t, expected, distance(pair.A, pair.B))
+	}
+}
+
+func TestKDTree_PairsWithin(t *testing.T) {
+	tree := kdtree.New([]kdtree.Point{&Point2D{X: 0, Y: 0}, &Point2D{X: 1, Y: 0}, &Point2D{X: 3, Y: 0}, &Point2D{X: 3, Y: 0}})
+	assert.Equal(t, []kdtree.Pair{}, tree.PairsWithin(-1))
+	assert.Equal(t, []kdtree.Pair{}, kdtree.New(nil).PairsWithin(1))
+
+	pairs := tree.PairsWithin(2)
+	assert.Len(t, pairs, 4)
+	assert.Equal(t, []float64{0, 1, 2, 2}, []float64{pairs[0].Distance, pairs[1].Distance, pairs[2].Distance, pairs[3].Distance})
+}
+
+func TestKDTree_PairsWithinWithGenerator(t *testing.T) {
+	tests := []struct {
+		name string
+		d    float64
+		dims int
+		size int
+	}{
+		{name: "p:1000,d:50,dims:2", d: 50, dims: 2, size: 1000},
+		{name: "p:2000,d:100,dims:2", d: 100, dims: 2, size: 2000},
+		{name: "p:1000,d:300,dims:3", d: 300, dims: 3, size: 1000},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			input := make([]kdtree.Point, 0, test.size)
+			for i := 0; i < test.size; i++ {
+				input = append(input, generateTestPoint(test.dims))
+			}
+			pairs := kdtree.New(append([]kdtree.Point(nil), input...)).PairsWithin(test.d)
+
+			var expected []float64
+			for i := range input {
+				for j := i + 1; j < len(input); j++ {
+					if d := distance(input[i], input[j]); d <= test.d {
+						expected = append(expected, d)
+					}
+				}
+			}
+			actual := make([]float64, 0, len(pairs))
+			for i, pair := range pairs {
+				assert.InDelta(t, distance(pair.A, pair.B), pair.Distance, 1e-9)
+				if i > 0 {
+					assert.LessOrEqual(t, pairs[i-1].Distance, pair.Distance)
+				}
+				actual = append(actual, pair.Distance)
+			}
+			assert.ElementsMatch(t, expected, actual)
+		})
+	}
+}
@@ -0,0 +1,65 @@
+/*
+ * Copyright 2020 Dennis Kuhnert
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package kdtree_test
+
+import (
+	"fmt"
+	"github.com/kyroy/kdtree"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestKDTree_KNNBoxPruning(t *testing.T) {
+	for _, dims := range []int{2, 3, 5, 8} {
+		t.Run(fmt.Sprintf("dims:%d", dims), func(t *testing.T) {
+			input := generateTestPoints(dims, 5000)
+			tree := kdtree.New(append([]kdtree.Point(nil), input[:2500]...))
+			for _, p := range input[2500:] {
+				tree.Insert(p)
+			}
+			for _, p := range input[:500] {
+				tree.Remove(p)
+			}
+
+			visited, planeVisited := 0, 0
+			for _, p := range generateTestPoints(dims, 50) {
+				assert.Equal(t, prioQueueKNN(input[500:], p, 10), tree.KNN(p, 10))
+				v, pv := kdtree.KNNVisited(tree, p, 10), kdtree.PlaneKNNVisited(tree, p, 10)
+				assert.LessOrEqual(t, v, pv)
+				visited += v
+				planeVisited += pv
+			}
+			assert.Less(t, visited, planeVisited)
+		})
+	}
+}
+
+func BenchmarkKNNVisitedNodes(b *testing.B) {
+	for _, dims := range []int{2, 3, 5, 8} {
+		tree := kdtree.New(generateTestPoints(dims, 100000))
+		queries := generateTestPoints(dims, 1000)
+		b.Run(fmt.Sprintf("dims:%d", dims), func(b *testing.B) {
+			visited, planeVisited := 0, 0
+			for i := 0; i < b.N; i++ {
+				visited += kdtree.KNNVisited(tree, queries[i%len(queries)], 10)
+				planeVisited += kdtree.PlaneKNNVisited(tree, queries[i%len(queries)], 10)
+			}
+			b.ReportMetric(float64(visited)/float64(b.N), "nodes/op")
+			b.ReportMetric(float64(planeVisited)/float64(b.N), "plane-nodes/op")
+		})
+	}
+}
@@ -0,0 +1,141 @@
+/*
+ * Copyright 2020 Dennis Kuhnert
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package kdtree
+
+import "github.com/kyroy/kdtree/kdrange"
+
+// ReverseNN returns all points of the tree that would have q as their nearest neighbour,
+// i.e. no other point of the tree is closer to them than q.
+//
+// Returns an empty slice when q is nil or its dimensions do not match the tree's dimensions.
+func (t *KDTree) ReverseNN(q Point) []Point {
+	return t.ReverseKNN(q, 1)
+}
+
+// ReverseKNN returns all points of the tree that would have q among their k-nearest neighbours,
+// i.e. less than k other points of the tree are closer to them than q.
+//
+// Subtrees are pruned with the perpendicular bisectors between q and its nearest neighbours:
+// a subtree is skipped when k+1 of them are closer than q to every point in its bounding box.
+//
+// Returns an empty slice when q is nil or its dimensions do not match the tree's dimensions.
+func (t *KDTree) ReverseKNN(q Point, k int) []Point {
+	if t.root == nil || q == nil || k <= 0 || q.Dimensions() != t.root.Dimensions() {
+		return []Point{}
+	}
+
+	filterSize := 2 * q.Dimensions() * (k + 1)
+	filter := newKNNSearch(q, filterSize)
+	filter.search(t.root, 1)
+	filters := make([]Point, 0, filter.nearest.Len())
+	for filter.nearest.Len() > 0 {
+		filters = append(filters, filter.nearest.PopLowest().(*node))
+	}
+
+	points := []Point{}
+	reverseKNN(q, k, filters, t.root, t.root, &points)
+	return points
+}
+
+func reverseKNN(q Point, k int, filters []Point, root, n *node, points *[]Point) {
+	if countCloserToBox(q, filters, n.Bounds, k+1) > k {
+		return
+	}
+
+	sqDistance := squaredDistance(n.Point, q)
+	if countCloserToPoint(q, filters, n.Point, k+1) <= k {
+		closer := root.countCloser(n.Point, sqDistance, k+1)
+		if sqDistance > 0 {
+			// n itself has the distance 0 < sqDistance
+			closer--
+		}
+		if closer < k {
+			*points = append(*points, n.Point)
+		}
+	}
+
+	if n.Left != nil {
+		reverseKNN(q, k, filters, root, n.Left, points)
+	}
+	if n.Right != nil {
+		reverseKNN(q, k, filters, root, n.Right, points)
+	}
+}
+
+// countCloser counts the points of the subtree whose squared distance to p is less than sqRadius.
+// Counting stops once limit is reached.
+func (n *node) countCloser(p Point, sqRadius float64, limit int) int {
+	if minSquaredDistance(p, n.Bounds) >= sqRadius {
+		return 0
+	}
+	if maxSquaredDistance(p, n.Bounds) < sqRadius {
+		return n.Size
+	}
+
+	count := 0
+	if squaredDistance(p, n.Point) < sqRadius {
+		count++
+	}
+	if n.Left != nil && count < limit {
+		count += n.Left.countCloser(p, sqRadius, limit-count)
+	}
+	if n.Right != nil && count < limit {
+		count += n.Right.countCloser(p, sqRadius, limit-count)
+	}
+	return count
+}
+
+// countCloserToPoint counts the filters that are closer to p than q, up to limit.
+func countCloserToPoint(q Point, filters []Point, p Point, limit int) int {
+	count := 0
+	for _, f := range filters {
+		if squaredDistance(p, f) < squaredDistance(p, q) {
+			count++
+			if count == limit {
+				break
+			}
+		}
+	}
+	return count
+}
+
+// countCloserToBox counts the filters that are closer than q to every point of the box b, up to limit.
+//
+// f is closer to a point p than q if p lies on f's side of their perpendicular bisector:
+// 2 p·(q-f) < |q|² - |f|². It holds for the whole box if it holds for the box corner maximizing p·(q-f).
+func countCloserToBox(q Point, filters []Point, b kdrange.Range, limit int) int {
+	count := 0
+	for _, f := range filters {
+		max, offset := 0., 0.
+		for dim, bounds := range b {
+			d := q.Dimension(dim) - f.Dimension(dim)
+			if d > 0 {
+				max += d * bounds[1]
+			} else {
+				max += d * bounds[0]
+			}
+			offset += q.Dimension(dim)*q.Dimension(dim) - f.Dimension(dim)*f.Dimension(dim)
+		}
+		if 2*max < offset {
+			count++
+			if count == limit {
+				break
+			}
+		}
+	}
+	return count
+}
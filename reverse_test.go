@@ -0,0 +1,119 @@
+/*
+ * Copyright 2020 Dennis Kuhnert
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package kdtree_test
+
+import (
+	"github.com/kyroy/kdtree"
+	. "github.com/kyroy/kdtree/points"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestKDTree_ReverseNN(t *testing.T) {
+	tests := []struct {
+		name   string
+		target kdtree.Point
+		input  []kdtree.Point
+		output []kdtree.Point
+	}{
+		{
+			name:   "nil",
+			target: nil,
+			input:  []kdtree.Point{&Point2D{X: 1., Y: 2.}},
+			output: []kdtree.Point{},
+		},
+		{
+			name:   "empty",
+			target: &Point2D{X: 1., Y: 2.},
+			input:  []kdtree.Point{},
+			output: []kdtree.Point{},
+		},
+		{
+			name:   "wrong dim",
+			target: &Point3D{X: 1., Y: 2.},
+			input:  []kdtree.Point{&Point2D{X: 1., Y: 2.}},
+			output: []kdtree.Point{},
+		},
+		{
+			name:   "single point",
+			target: &Point2D{X: 100, Y: 100},
+			input:  []kdtree.Point{&Point2D{X: 1., Y: 2.}},
+			output: []kdtree.Point{&Point2D{X: 1., Y: 2.}},
+		},
+		{
+			name:   "line",
+			target: &Point2D{X: 2.4, Y: 0},
+			input:  []kdtree.Point{&Point2D{X: 0, Y: 0}, &Point2D{X: 1, Y: 0}, &Point2D{X: 2, Y: 0}, &Point2D{X: 3, Y: 0}, &Point2D{X: 4, Y: 0}},
+			output: []kdtree.Point{&Point2D{X: 2, Y: 0}, &Point2D{X: 3, Y: 0}},
+		},
+		{
+			name:   "duplicates",
+			target: &Point2D{X: 1.5, Y: 0},
+			input:  []kdtree.Point{&Point2D{X: 1, Y: 0}, &Point2D{X: 1, Y: 0}, &Point2D{X: 2, Y: 0}},
+			output: []kdtree.Point{&Point2D{X: 2, Y: 0}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tree := kdtree.New(test.input)
+			assert.ElementsMatch(t, test.output, tree.ReverseNN(test.target))
+		})
+	}
+}
+
+func TestKDTree_ReverseKNNWithGenerator(t *testing.T) {
+	tests := []struct {
+		name string
+		k    int
+		dims int
+		size int
+	}{
+		{name: "p:1000,k:1,d:2", k: 1, dims: 2, size: 1000},
+		{name: "p:1000,k:5,d:2", k: 5, dims: 2, size: 1000},
+		{name: "p:1000,k:3,d:3", k: 3, dims: 3, size: 1000},
+		{name: "p:500,k:2,d:4", k: 2, dims: 4, size: 500},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			input := make([]kdtree.Point, 0, test.size)
+			for i := 0; i < test.size; i++ {
+				input = append(input, generateTestPoint(test.dims))
+			}
+			tree := kdtree.New(append([]kdtree.Point(nil), input...))
+			for i := 0; i < 5; i++ {
+				q := generateTestPoint(test.dims)
+				assert.ElementsMatch(t, bruteForceReverseKNN(input, q, test.k), tree.ReverseKNN(q, test.k))
+			}
+		})
+	}
+}
+
+func bruteForceReverseKNN(points []kdtree.Point, q kdtree.Point, k int) []kdtree.Point {
+	result := []kdtree.Point{}
+	for i, p := range points {
+		closer := 0
+		for j, o := range points {
+			if i != j && distance(p, o) < distance(p, q) {
+				closer++
+			}
+		}
+		if closer < k {
+			result = append(result, p)
+		}
+	}
+	return result
+}
@@ -0,0 +1,100 @@
+/*
+ * Copyright 2020 Dennis Kuhnert
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package kdtree
+
+import "math"
+
+// TreeStats describes the shape of a tree. See KDTree.Stats.
+type TreeStats struct {
+	// Len is the number of points.
+	Len int
+	// Height is the number of nodes on the longest path from the root to a leaf.
+	Height int
+	// Leaves is the number of nodes without children.
+	Leaves int
+	// Depths contains the number of nodes per depth. Depths[0] counts the root.
+	Depths []int
+	// Splits contains the number of nodes with children per splitting axis.
+	Splits []int
+	// Imbalance is the height divided by the height of a balanced tree with the same number of points.
+	// It is 1 for a balanced tree and 0 for an empty tree. Large values indicate that Balance should be called.
+	Imbalance float64
+}
+
+// Len returns the number of points in the tree.
+func (t *KDTree) Len() int {
+	if t.root == nil {
+		return 0
+	}
+	return t.root.Size
+}
+
+// Height returns the number of nodes on the longest path from the root to a leaf.
+// Returns 0 for an empty tree.
+func (t *KDTree) Height() int {
+	if t.root == nil {
+		return 0
+	}
+	return t.root.height()
+}
+
+// Stats returns a report about the shape of the tree.
+func (t *KDTree) Stats() TreeStats {
+	s := TreeStats{Depths: []int{}, Splits: []int{}}
+	if t.root == nil {
+		return s
+	}
+
+	s.Len = t.root.Size
+	s.Splits = make([]int, t.root.Dimensions())
+	t.root.shape(&s, 0)
+	s.Height = len(s.Depths)
+	s.Imbalance = float64(s.Height) / math.Ceil(math.Log2(float64(s.Len+1)))
+	return s
+}
+
+func (n *node) height() int {
+	height := 0
+	for _, child := range [2]*node{n.Left, n.Right} {
+		if child != nil {
+			if h := child.height(); h > height {
+				height = h
+			}
+		}
+	}
+	return height + 1
+}
+
+// shape adds the subtree at the given depth to s.
+func (n *node) shape(s *TreeStats, depth int) {
+	if depth == len(s.Depths) {
+		s.Depths = append(s.Depths, 0)
+	}
+	s.Depths[depth]++
+	if n.Left == nil && n.Right == nil {
+		s.Leaves++
+		return
+	}
+
+	s.Splits[n.Axis]++
+	if n.Left != nil {
+		n.Left.shape(s, depth+1)
+	}
+	if n.Right != nil {
+		n.Right.shape(s, depth+1)
+	}
+}
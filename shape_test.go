@@ -0,0 +1,112 @@
+/*
+ * Copyright 2020 Dennis Kuhnert
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package kdtree_test
+
+import (
+	"github.com/kyroy/kdtree"
+	. "github.com/kyroy/kdtree/points"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestKDTree_Len(t *testing.T) {
+	tree := kdtree.New(nil)
+	assert.Equal(t, 0, tree.Len())
+
+	input := generateTestCaseData(100)
+	for i, p := range input {
+		tree.Insert(p)
+		assert.Equal(t, i+1, tree.Len())
+	}
+	for i, p := range input[:50] {
+		tree.Remove(p)
+		assert.Equal(t, 99-i, tree.Len())
+	}
+	tree.Remove(&Point2D{X: 1e9, Y: 1e9})
+	assert.Equal(t, 50, tree.Len())
+	assert.Equal(t, 100, kdtree.New(input).Len())
+}
+
+func TestKDTree_Height(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  []kdtree.Point
+		height int
+	}{
+		{name: "empty", input: nil, height: 0},
+		{name: "1", input: []kdtree.Point{&Point2D{}}, height: 1},
+		{name: "3", input: []kdtree.Point{&Point2D{X: 1}, &Point2D{X: 2}, &Point2D{X: 3}}, height: 2},
+		{name: "4", input: []kdtree.Point{&Point2D{X: 1}, &Point2D{X: 2}, &Point2D{X: 3}, &Point2D{X: 4}}, height: 3},
+		{name: "1000", input: generateTestCaseData(1000), height: 10},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.height, kdtree.New(test.input).Height())
+		})
+	}
+}
+
+func TestKDTree_Stats(t *testing.T) {
+	assert.Equal(t, kdtree.TreeStats{Depths: []int{}, Splits: []int{}}, kdtree.New(nil).Stats())
+
+	// balanced tree with 7 points, the root splits in x, its children in y
+	var input []kdtree.Point
+	for i := 1; i <= 7; i++ {
+		input = append(input, &Point2D{X: float64(i), Y: float64(i)})
+	}
+	tree := kdtree.New(input)
+	assert.Equal(t, kdtree.TreeStats{
+		Len:       7,
+		Height:    3,
+		Leaves:    4,
+		Depths:    []int{1, 2, 4},
+		Splits:    []int{1, 2},
+		Imbalance: 1,
+	}, tree.Stats())
+
+	// a degenerated tree
+	tree = kdtree.New(nil)
+	for _, p := range input {
+		tree.Insert(p)
+	}
+	assert.Equal(t, kdtree.TreeStats{
+		Len:       7,
+		Height:    7,
+		Leaves:    1,
+		Depths:    []int{1, 1, 1, 1, 1, 1, 1},
+		Splits:    []int{3, 3},
+		Imbalance: 7. / 3,
+	}, tree.Stats())
+
+	tree.Balance()
+	assert.Equal(t, 1., tree.Stats().Imbalance)
+}
+
+func TestKDTree_StatsShapeWithGenerator(t *testing.T) {
+	tree := kdtree.New(generateTestCaseData(1000))
+	stats := tree.Stats()
+	assert.Equal(t, tree.Len(), stats.Len)
+	assert.Equal(t, tree.Height(), stats.Height)
+
+	sum := 0
+	for _, d := range stats.Depths {
+		sum += d
+	}
+	assert.Equal(t, stats.Len, sum)
+	assert.Equal(t, stats.Len, stats.Leaves+stats.Splits[0]+stats.Splits[1])
+	assert.Equal(t, 1., stats.Imbalance)
+}
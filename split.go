@@ -0,0 +1,140 @@
+/*
+ * Copyright 2020 Dennis Kuhnert
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package kdtree
+
+import "sort"
+
+// Splitter chooses how the points of a subtree are split when the tree is built.
+type Splitter interface {
+	// Split returns the axis that splits the points and the index of the point that becomes the node.
+	// Split orders points, such that the points before the index are not larger than the node's point
+	// and the points after it are not smaller, in the chosen axis.
+	// parentAxis is the axis of the parent node, -1 for the root.
+	Split(points []Point, parentAxis int) (axis, index int)
+}
+
+var (
+	// RoundRobin cycles through the axes and splits at the median. This is the default.
+	RoundRobin Splitter = roundRobin{}
+	// MaxSpread splits at the median of the axis in which the points are spread the most.
+	MaxSpread Splitter = maxSpread{}
+	// MaxVariance splits at the median of the axis in which the points have the largest variance.
+	MaxVariance Splitter = maxVariance{}
+	// SlidingMidpoint splits the axis in which the points are spread the most at the point nearest to the middle.
+	// It adapts better to clustered data than a median split, but the tree may become unbalanced.
+	SlidingMidpoint Splitter = slidingMidpoint{}
+)
+
+// WithSplitter sets the Splitter that is used to build the tree in New and Balance.
+// Points added by Insert always split at the axis following the one of their parent.
+func WithSplitter(s Splitter) Option {
+	return func(t *KDTree) {
+		t.splitter = s
+	}
+}
+
+type roundRobin struct{}
+
+func (roundRobin) Split(points []Point, parentAxis int) (int, int) {
+	axis := nextAxis(points, parentAxis)
+	sort.Sort(&byDimension{dimension: axis, points: points})
+	return axis, len(points) / 2
+}
+
+type maxSpread struct{}
+
+func (maxSpread) Split(points []Point, parentAxis int) (int, int) {
+	axis := largestAxis(points, parentAxis, spread)
+	sort.Sort(&byDimension{dimension: axis, points: points})
+	return axis, len(points) / 2
+}
+
+type maxVariance struct{}
+
+func (maxVariance) Split(points []Point, parentAxis int) (int, int) {
+	axis := largestAxis(points, parentAxis, variance)
+	sort.Sort(&byDimension{dimension: axis, points: points})
+	return axis, len(points) / 2
+}
+
+type slidingMidpoint struct{}
+
+func (slidingMidpoint) Split(points []Point, parentAxis int) (int, int) {
+	axis := largestAxis(points, parentAxis, spread)
+	sort.Sort(&byDimension{dimension: axis, points: points})
+
+	// if all points are on one side of the midpoint, the split slides to the nearest point
+	mid := (points[0].Dimension(axis) + points[len(points)-1].Dimension(axis)) / 2
+	i := sort.Search(len(points), func(i int) bool {
+		return points[i].Dimension(axis) >= mid
+	})
+	if i == len(points) || (i > 0 && mid-points[i-1].Dimension(axis) < points[i].Dimension(axis)-mid) {
+		i--
+	}
+	// equal points belong to the right subtree
+	for i > 0 && points[i-1].Dimension(axis) == points[i].Dimension(axis) {
+		i--
+	}
+	// an empty left subtree turns duplicates into a list, the median keeps the tree balanced
+	if i == 0 {
+		i = len(points) / 2
+	}
+	return axis, i
+}
+
+func nextAxis(points []Point, parentAxis int) int {
+	return (parentAxis + 1) % points[0].Dimensions()
+}
+
+// largestAxis returns the axis with the largest measure of the points.
+// Ties are resolved in favor of the axis following the parent's axis.
+func largestAxis(points []Point, parentAxis int, measure func(points []Point, axis int) float64) int {
+	best := nextAxis(points, parentAxis)
+	bestMeasure := measure(points, best)
+	for axis := 0; axis < points[0].Dimensions(); axis++ {
+		if m := measure(points, axis); m > bestMeasure {
+			best, bestMeasure = axis, m
+		}
+	}
+	return best
+}
+
+func spread(points []Point, axis int) float64 {
+	min, max := points[0].Dimension(axis), points[0].Dimension(axis)
+	for _, p := range points[1:] {
+		if v := p.Dimension(axis); v < min {
+			min = v
+		} else if v > max {
+			max = v
+		}
+	}
+	return max - min
+}
+
+func variance(points []Point, axis int) float64 {
+	mean := 0.
+	for _, p := range points {
+		mean += p.Dimension(axis)
+	}
+	mean /= float64(len(points))
+	sum := 0.
+	for _, p := range points {
+		d := p.Dimension(axis) - mean
+		sum += d * d
+	}
+	return sum / float64(len(points))
+}
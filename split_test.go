@@ -0,0 +1,191 @@
+/*
+ * Copyright 2020 Dennis Kuhnert
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package kdtree_test
+
+import (
+	"fmt"
+	"github.com/kyroy/kdtree"
+	"github.com/kyroy/kdtree/kdtreetest"
+	. "github.com/kyroy/kdtree/points"
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"testing"
+)
+
+func TestSplitter_Root(t *testing.T) {
+	input := []kdtree.Point{
+		&Point2D{X: 0, Y: 0}, &Point2D{X: 1, Y: 1}, &Point2D{X: 2, Y: 2},
+		&Point2D{X: 3, Y: 3}, &Point2D{X: 4, Y: 60}, &Point2D{X: 4, Y: 100},
+	}
+	tests := []struct {
+		name     string
+		splitter kdtree.Splitter
+		axis     int
+		point    kdtree.Point
+	}{
+		{name: "round robin", splitter: kdtree.RoundRobin, axis: 0, point: &Point2D{X: 3, Y: 3}},
+		{name: "max spread", splitter: kdtree.MaxSpread, axis: 1, point: &Point2D{X: 3, Y: 3}},
+		{name: "max variance", splitter: kdtree.MaxVariance, axis: 1, point: &Point2D{X: 3, Y: 3}},
+		{name: "sliding midpoint", splitter: kdtree.SlidingMidpoint, axis: 1, point: &Point2D{X: 4, Y: 60}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			root := kdtree.New(append([]kdtree.Point(nil), input...), kdtree.WithSplitter(test.splitter)).Root()
+			assert.Equal(t, test.axis, root.Axis())
+			assert.Equal(t, test.point, root.Point())
+			assertSplits(t, root)
+		})
+	}
+}
+
+func TestSlidingMidpoint_Split(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  []kdtree.Point
+		output int
+	}{
+		{name: "single", input: []kdtree.Point{&Point2D{X: 1}}, output: 0},
+		{name: "slide left", input: []kdtree.Point{&Point2D{X: 0}, &Point2D{X: 1}, &Point2D{X: 2}, &Point2D{X: 10}}, output: 2},
+		{name: "slide right", input: []kdtree.Point{&Point2D{X: 0}, &Point2D{X: 8}, &Point2D{X: 9}, &Point2D{X: 10}}, output: 1},
+		{name: "equal points", input: []kdtree.Point{&Point2D{X: 0}, &Point2D{X: 5}, &Point2D{X: 5}, &Point2D{X: 10}}, output: 1},
+		{name: "all equal", input: []kdtree.Point{&Point2D{X: 3}, &Point2D{X: 3}, &Point2D{X: 3}}, output: 1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			axis, index := kdtree.SlidingMidpoint.Split(test.input, -1)
+			assert.Equal(t, 0, axis)
+			assert.Equal(t, test.output, index)
+		})
+	}
+}
+
+func TestSlidingMidpoint_Duplicates(t *testing.T) {
+	tests := []struct {
+		name  string
+		input func(i int) kdtree.Point
+	}{
+		{name: "identical", input: func(i int) kdtree.Point { return &Point2D{X: 1, Y: 2} }},
+		{name: "few values", input: func(i int) kdtree.Point { return &Point2D{X: float64(i % 3), Y: 2} }},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			input := make([]kdtree.Point, 0, 20000)
+			for i := 0; i < cap(input); i++ {
+				input = append(input, test.input(i))
+			}
+			tree := kdtree.New(input, kdtree.WithSplitter(kdtree.SlidingMidpoint))
+			assert.Equal(t, 20000, tree.Len())
+			assert.LessOrEqual(t, tree.Height(), 20)
+			assert.NoError(t, tree.Validate())
+		})
+	}
+}
+
+func TestSplitterWithGenerator(t *testing.T) {
+	for _, s := range kdtreetest.Splitters {
+		for _, data := range []struct {
+			name  string
+			input []kdtree.Point
+		}{
+			{name: "uniform", input: generateTestCaseData(2000)},
+			{name: "elongated clusters", input: generateElongatedClusters(10, 200)},
+			{name: "3d", input: generateTestPoints(3, 1000)},
+		} {
+			t.Run(fmt.Sprintf("%s %s", s.Name, data.name), func(t *testing.T) {
+				input := data.input
+				tree := kdtree.New(append([]kdtree.Point(nil), input[:len(input)/2]...), kdtree.WithSplitter(s.Splitter))
+				for _, p := range input[len(input)/2:] {
+					tree.Insert(p)
+				}
+				for _, p := range input[:len(input)/4] {
+					tree.Remove(p)
+				}
+				input = input[len(input)/4:]
+				assertSplits(t, tree.Root())
+
+				for i := 0; i < 20; i++ {
+					p := input[rand.Intn(len(input))]
+					assert.Equal(t, prioQueueKNN(input, p, 10), tree.KNN(p, 10))
+				}
+				r := kdtree.New(input).Root().Bounds().Expand(-100)
+				assert.ElementsMatch(t, filterRangeSearch(input, r), tree.RangeSearch(r))
+
+				tree.Balance()
+				assertSplits(t, tree.Root())
+				assert.Equal(t, len(input), tree.Root().Len())
+			})
+		}
+	}
+}
+
+func BenchmarkSplitterKNN(b *testing.B) {
+	input := generateElongatedClusters(100, 1000)
+	for _, s := range kdtreetest.Splitters {
+		tree := kdtree.New(append([]kdtree.Point(nil), input...), kdtree.WithSplitter(s.Splitter))
+		b.Run(s.Name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				resultPoints = tree.KNN(input[i%len(input)], 10)
+			}
+		})
+	}
+}
+
+// assertSplits asserts that all points left of a node are not larger and all points right of it not smaller in its axis.
+func assertSplits(t *testing.T, c kdtree.Cell) {
+	if c.IsEmpty() {
+		return
+	}
+	axis, v := c.Axis(), c.Point().Dimension(c.Axis())
+	if left := c.Left(); !left.IsEmpty() {
+		assert.LessOrEqual(t, left.Bounds()[axis][1], v)
+	}
+	if right := c.Right(); !right.IsEmpty() {
+		assert.GreaterOrEqual(t, right.Bounds()[axis][0], v)
+	}
+	assertSplits(t, c.Left())
+	assertSplits(t, c.Right())
+}
+
+// generateElongatedClusters returns clusters of n points each, stretched along a random axis.
+func generateElongatedClusters(clusters, n int) []kdtree.Point {
+	r := rand.New(rand.NewSource(1))
+	var points []kdtree.Point
+	for c := 0; c < clusters; c++ {
+		x, y := r.Float64()*3000-1500, r.Float64()*3000-1500
+		sx, sy := 200., 2.
+		if r.Intn(2) == 0 {
+			sx, sy = sy, sx
+		}
+		for i := 0; i < n; i++ {
+			points = append(points, &Point2D{X: x + r.NormFloat64()*sx, Y: y + r.NormFloat64()*sy})
+		}
+	}
+	return points
+}
+
+func generateTestPoints(dimensions, n int) []kdtree.Point {
+	r := rand.New(rand.NewSource(2))
+	points := make([]kdtree.Point, n)
+	for i := range points {
+		values := make([]float64, dimensions)
+		for j := range values {
+			values[j] = r.Float64()*3000 - 1500
+		}
+		points[i] = NewPoint(values, nil)
+	}
+	return points
+}
@@ -0,0 +1,85 @@
+/*
+ * Copyright 2020 Dennis Kuhnert
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package kdtree
+
+// QueryStats contains statistics about queries. See WithQueryStats.
+// The shape of the tree is described by KDTree.Stats.
+type QueryStats struct {
+	// NodesVisited is the number of nodes whose point was examined.
+	NodesVisited int
+	// DistanceComputations is the number of distances computed between the query point and a point of the tree.
+	// Range queries test the points against the region without computing distances.
+	DistanceComputations int
+	// QueueInserts is the number of points inserted into the queue of the nearest neighbours.
+	QueueInserts int
+	// MaxDepth is the depth of the deepest visited node. The root has the depth 1.
+	MaxDepth int
+}
+
+// QueryOption configures a single query.
+type QueryOption func(*query)
+
+type query struct {
+	stats *QueryStats
+}
+
+// WithQueryStats adds the statistics of the query to s.
+// s is not reset, so it can collect the statistics of multiple queries.
+func WithQueryStats(s *QueryStats) QueryOption {
+	return func(q *query) {
+		q.stats = s
+	}
+}
+
+func newQuery(options []QueryOption) *query {
+	q := &query{}
+	for _, option := range options {
+		option(q)
+	}
+	return q
+}
+
+// finish adds the statistics of the finished query to the requested QueryStats.
+func (q *query) finish(s *QueryStats) {
+	if q.stats != nil {
+		q.stats.Add(*s)
+	}
+}
+
+// RequestedQueryStats returns the QueryStats requested with WithQueryStats. Returns nil if none was requested.
+// It allows other indexes to support the QueryOptions of this package.
+func RequestedQueryStats(options ...QueryOption) *QueryStats {
+	return newQuery(options).stats
+}
+
+// Add adds the statistics of o to s. MaxDepth becomes the larger of both.
+func (s *QueryStats) Add(o QueryStats) {
+	s.NodesVisited += o.NodesVisited
+	s.DistanceComputations += o.DistanceComputations
+	s.QueueInserts += o.QueueInserts
+	if o.MaxDepth > s.MaxDepth {
+		s.MaxDepth = o.MaxDepth
+	}
+}
+
+// visit records a visited node at the given depth.
+func (s *QueryStats) visit(depth int) {
+	s.NodesVisited++
+	if depth > s.MaxDepth {
+		s.MaxDepth = depth
+	}
+}
@@ -0,0 +1,113 @@
+/*
+ * Copyright 2020 Dennis Kuhnert
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package kdtree_test
+
+import (
+	"github.com/kyroy/kdtree"
+	"github.com/kyroy/kdtree/kdrange"
+	. "github.com/kyroy/kdtree/points"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestKDTree_KNNWithQueryStats(t *testing.T) {
+	// balanced tree with 4 as root, 2 and 6 as its children and 1, 3, 5 and 7 as leaves
+	var input []kdtree.Point
+	for i := 1; i <= 7; i++ {
+		input = append(input, NewPoint([]float64{float64(i)}, nil))
+	}
+	tree := kdtree.New(input)
+
+	var stats kdtree.QueryStats
+	tree.KNN(NewPoint([]float64{4}, nil), 1, kdtree.WithQueryStats(&stats))
+	assert.Equal(t, kdtree.QueryStats{NodesVisited: 3, DistanceComputations: 3, QueueInserts: 2, MaxDepth: 3}, stats)
+
+	// statistics are accumulated
+	tree.KNN(NewPoint([]float64{4}, nil), 1, kdtree.WithQueryStats(&stats))
+	assert.Equal(t, kdtree.QueryStats{NodesVisited: 6, DistanceComputations: 6, QueueInserts: 4, MaxDepth: 3}, stats)
+
+	stats = kdtree.QueryStats{}
+	tree.KNN(NewPoint([]float64{4}, nil), 7, kdtree.WithQueryStats(&stats))
+	assert.Equal(t, kdtree.QueryStats{NodesVisited: 7, DistanceComputations: 7, QueueInserts: 7, MaxDepth: 3}, stats)
+}
+
+func TestKDTree_RangeSearchWithQueryStats(t *testing.T) {
+	var input []kdtree.Point
+	for i := 1; i <= 7; i++ {
+		input = append(input, NewPoint([]float64{float64(i)}, nil))
+	}
+	tree := kdtree.New(input)
+
+	tests := []struct {
+		name  string
+		r     kdrange.Region
+		stats kdtree.QueryStats
+	}{
+		{name: "outside", r: kdrange.New(8, 9), stats: kdtree.QueryStats{}},
+		{name: "all", r: kdrange.New(0, 9), stats: kdtree.QueryStats{NodesVisited: 7, MaxDepth: 3}},
+		{name: "partial", r: kdrange.New(2, 3), stats: kdtree.QueryStats{NodesVisited: 3, MaxDepth: 3}},
+		{name: "root only", r: kdrange.New(3.5, 4.5), stats: kdtree.QueryStats{NodesVisited: 1, MaxDepth: 1}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var stats kdtree.QueryStats
+			tree.RangeSearch(test.r, kdtree.WithQueryStats(&stats))
+			assert.Equal(t, test.stats, stats)
+		})
+	}
+}
+
+func TestKDTree_StatsDegenerateTree(t *testing.T) {
+	tree := kdtree.New(nil)
+	for i := 0; i < 100; i++ {
+		tree.Insert(&Point2D{X: float64(i), Y: float64(i)})
+	}
+
+	var stats kdtree.QueryStats
+	tree.KNN(&Point2D{X: 100, Y: 100}, 1, kdtree.WithQueryStats(&stats))
+	assert.Equal(t, 100, stats.MaxDepth)
+
+	stats = kdtree.QueryStats{}
+	tree.RangeSearch(kdrange.New(-1, 100, -1, 100), kdtree.WithQueryStats(&stats))
+	assert.Equal(t, kdtree.QueryStats{NodesVisited: 100, MaxDepth: 100}, stats)
+}
+
+func TestKDTree_StatsWithGenerator(t *testing.T) {
+	input := generateTestCaseData(1000)
+	tree := kdtree.New(input)
+
+	var stats kdtree.QueryStats
+	for _, p := range generateTestCaseData(100) {
+		tree.KNN(p, 5, kdtree.WithQueryStats(&stats))
+	}
+	assert.Equal(t, stats.NodesVisited, stats.DistanceComputations)
+	assert.LessOrEqual(t, stats.QueueInserts, stats.NodesVisited)
+	assert.GreaterOrEqual(t, stats.QueueInserts, 100*5)
+	assert.Less(t, stats.NodesVisited, 100*1000)
+	assert.LessOrEqual(t, stats.MaxDepth, 10)
+}
+
+func TestRequestedQueryStats(t *testing.T) {
+	assert.Nil(t, kdtree.RequestedQueryStats())
+
+	var stats kdtree.QueryStats
+	assert.Same(t, &stats, kdtree.RequestedQueryStats(kdtree.WithQueryStats(&stats)))
+
+	stats.Add(kdtree.QueryStats{NodesVisited: 3, DistanceComputations: 2, QueueInserts: 1, MaxDepth: 4})
+	stats.Add(kdtree.QueryStats{NodesVisited: 1, DistanceComputations: 1, QueueInserts: 1, MaxDepth: 2})
+	assert.Equal(t, kdtree.QueryStats{NodesVisited: 4, DistanceComputations: 3, QueueInserts: 2, MaxDepth: 4}, stats)
+}
@@ -0,0 +1,114 @@
+/*
+ * Copyright 2020 Dennis Kuhnert
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package kdtree
+
+import (
+	"fmt"
+	"github.com/kyroy/kdtree/kdrange"
+	"math"
+)
+
+// Validate checks the structure of the tree and returns an error describing the first violation.
+// The error contains the path to the offending node, e.g. "root.Left.Right".
+//
+// It checks that all points have the same dimensions, that every node splits its subtree on a valid axis,
+// such that no point in the left subtree is larger and no point in the right subtree is smaller in that axis,
+// and that the cached size, bounding box and coordinate sums of every subtree are correct.
+func (t *KDTree) Validate() error {
+	if t.root == nil {
+		return nil
+	}
+	if t.root.Point == nil {
+		return fmt.Errorf("kdtree: root: nil point")
+	}
+	_, err := t.root.validate("root", t.root.Dimensions())
+	return err
+}
+
+// aggregate contains the actual size, bounding box and coordinate sums of a subtree.
+type aggregate struct {
+	size   int
+	bounds kdrange.Range
+	sum    []float64
+}
+
+// validate checks the subtree n and returns its actual aggregate.
+func (n *node) validate(path string, dims int) (*aggregate, error) {
+	if n.Point == nil {
+		return nil, fmt.Errorf("kdtree: %s: nil point", path)
+	}
+	if n.Dimensions() != dims {
+		return nil, fmt.Errorf("kdtree: %s: point %v has %d dimensions, expected %d", path, n.Point, n.Dimensions(), dims)
+	}
+	if n.Axis < 0 || n.Axis >= dims {
+		return nil, fmt.Errorf("kdtree: %s: axis %d out of range [0, %d)", path, n.Axis, dims)
+	}
+
+	a := &aggregate{size: 1, bounds: make(kdrange.Range, dims), sum: make([]float64, dims)}
+	for i := range a.bounds {
+		a.bounds[i] = [2]float64{n.Dimension(i), n.Dimension(i)}
+		a.sum[i] = n.Dimension(i)
+	}
+	split := n.Dimension(n.Axis)
+	if n.Left != nil {
+		left, err := n.Left.validate(path+".Left", dims)
+		if err != nil {
+			return nil, err
+		}
+		if largest := left.bounds[n.Axis][1]; largest > split {
+			return nil, fmt.Errorf("kdtree: %s: left subtree contains %v > %v in axis %d of point %v", path, largest, split, n.Axis, n.Point)
+		}
+		a.add(left)
+	}
+	if n.Right != nil {
+		right, err := n.Right.validate(path+".Right", dims)
+		if err != nil {
+			return nil, err
+		}
+		if smallest := right.bounds[n.Axis][0]; smallest < split {
+			return nil, fmt.Errorf("kdtree: %s: right subtree contains %v < %v in axis %d of point %v", path, smallest, split, n.Axis, n.Point)
+		}
+		a.add(right)
+	}
+
+	if n.Size != a.size {
+		return nil, fmt.Errorf("kdtree: %s: cached size %d, actual %d", path, n.Size, a.size)
+	}
+	if len(n.Bounds) != dims || len(n.Sum) != dims {
+		return nil, fmt.Errorf("kdtree: %s: cached bounds or sums do not have %d dimensions", path, dims)
+	}
+	for i := 0; i < dims; i++ {
+		if n.Bounds[i] != a.bounds[i] {
+			return nil, fmt.Errorf("kdtree: %s: cached bounds %v in axis %d, actual %v", path, n.Bounds[i], i, a.bounds[i])
+		}
+		// the cached sums are updated incrementally, so rounding errors are tolerated
+		tolerance := 1e-9 * math.Max(1, float64(a.size)*math.Max(math.Abs(a.bounds[i][0]), math.Abs(a.bounds[i][1])))
+		if math.Abs(n.Sum[i]-a.sum[i]) > tolerance {
+			return nil, fmt.Errorf("kdtree: %s: cached sum %v in axis %d, actual %v", path, n.Sum[i], i, a.sum[i])
+		}
+	}
+	return a, nil
+}
+
+func (a *aggregate) add(o *aggregate) {
+	a.size += o.size
+	for i := range a.bounds {
+		a.bounds[i][0] = math.Min(a.bounds[i][0], o.bounds[i][0])
+		a.bounds[i][1] = math.Max(a.bounds[i][1], o.bounds[i][1])
+		a.sum[i] += o.sum[i]
+	}
+}
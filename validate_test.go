@@ -0,0 +1,123 @@
+/*
+ * Copyright 2020 Dennis Kuhnert
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package kdtree_test
+
+import (
+	"github.com/kyroy/kdtree"
+	"github.com/kyroy/kdtree/kdtreetest"
+	. "github.com/kyroy/kdtree/points"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestKDTree_Validate(t *testing.T) {
+	newTree := func() *kdtree.KDTree {
+		var input []kdtree.Point
+		for i := 1; i <= 7; i++ {
+			input = append(input, &Point2D{X: float64(i), Y: float64(8 - i)})
+		}
+		return kdtree.New(input)
+	}
+
+	tests := []struct {
+		name    string
+		corrupt func(root *kdtree.Node)
+		err     string
+	}{
+		{name: "valid", corrupt: func(root *kdtree.Node) {}},
+		{
+			name:    "dimensions",
+			corrupt: func(root *kdtree.Node) { root.Left.Right.Point = &Point3D{} },
+			err:     "kdtree: root.Left.Right: point {0.00 0.00 0.00} has 3 dimensions, expected 2",
+		},
+		{
+			name:    "nil point",
+			corrupt: func(root *kdtree.Node) { root.Right.Point = nil },
+			err:     "kdtree: root.Right: nil point",
+		},
+		{
+			name:    "axis",
+			corrupt: func(root *kdtree.Node) { root.Right.Axis = 2 },
+			err:     "kdtree: root.Right: axis 2 out of range [0, 2)",
+		},
+		{
+			name:    "left split",
+			corrupt: func(root *kdtree.Node) { root.Point = &Point2D{X: 2.5, Y: 4} },
+			err:     "kdtree: root: left subtree contains 3 > 2.5 in axis 0 of point {2.50 4.00}",
+		},
+		{
+			name:    "right split",
+			corrupt: func(root *kdtree.Node) { root.Left, root.Right = root.Right, root.Left },
+			err:     "kdtree: root: left subtree contains 7 > 4 in axis 0 of point {4.00 4.00}",
+		},
+		{
+			name:    "right split in y",
+			corrupt: func(root *kdtree.Node) { root.Right.Point = &Point2D{X: 6, Y: 3.5} },
+			err:     "kdtree: root.Right: right subtree contains 3 < 3.5 in axis 1 of point {6.00 3.50}",
+		},
+		{
+			name:    "size",
+			corrupt: func(root *kdtree.Node) { root.Left.Size = 2 },
+			err:     "kdtree: root.Left: cached size 2, actual 3",
+		},
+		{
+			name:    "bounds",
+			corrupt: func(root *kdtree.Node) { root.Bounds[1][0] = 0 },
+			err:     "kdtree: root: cached bounds [0 7] in axis 1, actual [1 7]",
+		},
+		{
+			name:    "sum",
+			corrupt: func(root *kdtree.Node) { root.Right.Sum[0]++ },
+			err:     "kdtree: root.Right: cached sum 19 in axis 0, actual 18",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tree := newTree()
+			test.corrupt(kdtree.RootNode(tree))
+			err := tree.Validate()
+			if test.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, test.err)
+		})
+	}
+}
+
+func TestKDTree_ValidateWithGenerator(t *testing.T) {
+	assert.NoError(t, kdtree.New(nil).Validate())
+
+	for _, s := range kdtreetest.Splitters {
+		t.Run(s.Name, func(t *testing.T) {
+			input := generateTestCaseData(2000)
+			tree := kdtree.New(append([]kdtree.Point(nil), input[:1000]...), kdtree.WithSplitter(s.Splitter))
+			assert.NoError(t, tree.Validate())
+			for _, p := range input[1000:] {
+				tree.Insert(p)
+			}
+			assert.NoError(t, tree.Validate())
+			for i, p := range input[:1500] {
+				tree.Remove(p)
+				if i%100 == 0 {
+					assert.NoError(t, tree.Validate())
+				}
+			}
+			assert.NoError(t, tree.Validate())
+		})
+	}
+}
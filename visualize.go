@@ -0,0 +1,157 @@
+/*
+ * Copyright 2020 Dennis Kuhnert
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package kdtree
+
+import (
+	"fmt"
+	"github.com/kyroy/kdtree/kdrange"
+	"io"
+	"math"
+	"strings"
+)
+
+// WriteDOT writes the tree in the Graphviz DOT language to w.
+// Every node is labeled with its point and splitting axis, the edges with the side of the split.
+//
+// Render it e.g. with: dot -Tpng tree.dot -o tree.png
+func (t *KDTree) WriteDOT(w io.Writer) error {
+	ew := &errWriter{w: w}
+	ew.printf("digraph kdtree {\n")
+	ew.printf("\tnode [shape=box];\n")
+	if t.root != nil {
+		id := 0
+		t.root.writeDOT(ew, &id)
+	}
+	ew.printf("}\n")
+	return ew.err
+}
+
+// writeDOT writes the subtree with the next free id and returns the id of n.
+func (n *node) writeDOT(ew *errWriter, id *int) int {
+	nID := *id
+	*id++
+	ew.printf("\tn%d [label=\"%s\\naxis %d\"];\n", nID, escapeDOT(fmt.Sprintf("%v", n.Point)), n.Axis)
+	if n.Left != nil {
+		ew.printf("\tn%d -> n%d [label=\"<\"];\n", nID, n.Left.writeDOT(ew, id))
+	}
+	if n.Right != nil {
+		ew.printf("\tn%d -> n%d [label=\">=\"];\n", nID, n.Right.writeDOT(ew, id))
+	}
+	return nID
+}
+
+func escapeDOT(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
+
+// WriteSVG writes an SVG image of a 2-dimensional tree with the given size in pixels to w.
+// It shows the points and the splitting lines that partition the plane, colored by their axis.
+//
+// Returns an error when the points of the tree do not have 2 dimensions.
+func (t *KDTree) WriteSVG(w io.Writer, width, height int) error {
+	if t.root != nil && t.root.Dimensions() != 2 {
+		return fmt.Errorf("kdtree: SVG requires 2 dimensions, got %d", t.root.Dimensions())
+	}
+
+	ew := &errWriter{w: w}
+	ew.printf("<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" viewBox=\"0 0 %d %d\">\n", width, height, width, height)
+	ew.printf("<rect width=\"%d\" height=\"%d\" fill=\"white\"/>\n", width, height)
+	if t.root != nil {
+		s := newSVGScale(t.root.Bounds, width, height)
+		bounds := [2][2]float64{t.root.Bounds[0], t.root.Bounds[1]}
+		ew.printf("<rect x=\"%.2f\" y=\"%.2f\" width=\"%.2f\" height=\"%.2f\" fill=\"none\" stroke=\"gray\"/>\n",
+			s.x(bounds[0][0]), s.y(bounds[1][1]), s.x(bounds[0][1])-s.x(bounds[0][0]), s.y(bounds[1][0])-s.y(bounds[1][1]))
+		t.root.writeSVGLines(ew, s, bounds)
+		for _, p := range t.root.Points() {
+			ew.printf("<circle cx=\"%.2f\" cy=\"%.2f\" r=\"2\" fill=\"black\"/>\n", s.x(p.Dimension(0)), s.y(p.Dimension(1)))
+		}
+	}
+	ew.printf("</svg>\n")
+	return ew.err
+}
+
+// splitColors contains the colors of the splitting lines in x and y.
+var splitColors = [2]string{"red", "blue"}
+
+// writeSVGLines writes the splitting lines of the subtree, which is contained in the cell.
+func (n *node) writeSVGLines(ew *errWriter, s *svgScale, cell [2][2]float64) {
+	if n.Left == nil && n.Right == nil {
+		return
+	}
+
+	split := n.Dimension(n.Axis)
+	if n.Axis == 0 {
+		ew.printf("<line x1=\"%.2f\" y1=\"%.2f\" x2=\"%.2f\" y2=\"%.2f\" stroke=\"%s\"/>\n",
+			s.x(split), s.y(cell[1][0]), s.x(split), s.y(cell[1][1]), splitColors[0])
+	} else {
+		ew.printf("<line x1=\"%.2f\" y1=\"%.2f\" x2=\"%.2f\" y2=\"%.2f\" stroke=\"%s\"/>\n",
+			s.x(cell[0][0]), s.y(split), s.x(cell[0][1]), s.y(split), splitColors[1])
+	}
+	if n.Left != nil {
+		left := cell
+		left[n.Axis][1] = split
+		n.Left.writeSVGLines(ew, s, left)
+	}
+	if n.Right != nil {
+		right := cell
+		right[n.Axis][0] = split
+		n.Right.writeSVGLines(ew, s, right)
+	}
+}
+
+// svgScale maps the coordinates of the points to pixels with a margin. The y axis points upwards.
+type svgScale struct {
+	min    [2]float64
+	factor float64
+	height int
+	margin float64
+}
+
+func newSVGScale(bounds kdrange.Range, width, height int) *svgScale {
+	s := &svgScale{min: [2]float64{bounds[0][0], bounds[1][0]}, height: height, margin: 10}
+	// keep the aspect ratio, degenerated extents are treated as 1
+	extentX, extentY := bounds[0][1]-bounds[0][0], bounds[1][1]-bounds[1][0]
+	if extentX == 0 {
+		extentX = 1
+	}
+	if extentY == 0 {
+		extentY = 1
+	}
+	s.factor = math.Min((float64(width)-2*s.margin)/extentX, (float64(height)-2*s.margin)/extentY)
+	return s
+}
+
+func (s *svgScale) x(v float64) float64 {
+	return s.margin + (v-s.min[0])*s.factor
+}
+
+func (s *svgScale) y(v float64) float64 {
+	return float64(s.height) - s.margin - (v-s.min[1])*s.factor
+}
+
+// errWriter writes to w until the first error occurs.
+type errWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (ew *errWriter) printf(format string, args ...interface{}) {
+	if ew.err != nil {
+		return
+	}
+	_, ew.err = fmt.Fprintf(ew.w, format, args...)
+}
@@ -0,0 +1,123 @@
+/*
+ * Copyright 2020 Dennis Kuhnert
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package kdtree_test
+
+import (
+	"bytes"
+	"errors"
+	"github.com/kyroy/kdtree"
+	. "github.com/kyroy/kdtree/points"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestKDTree_WriteDOT(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  []kdtree.Point
+		output string
+	}{
+		{name: "empty", input: nil, output: "digraph kdtree {\n\tnode [shape=box];\n}\n"},
+		{
+			name:  "tree",
+			input: []kdtree.Point{&Point2D{X: 1, Y: 2}, &Point2D{X: 3, Y: 1}, &Point2D{X: 2, Y: 3}, &Point2D{X: 4, Y: 0}},
+			output: `digraph kdtree {
+	node [shape=box];
+	n0 [label="{3.00 1.00}\naxis 0"];
+	n1 [label="{2.00 3.00}\naxis 1"];
+	n2 [label="{1.00 2.00}\naxis 0"];
+	n1 -> n2 [label="<"];
+	n0 -> n1 [label="<"];
+	n3 [label="{4.00 0.00}\naxis 1"];
+	n0 -> n3 [label=">="];
+}
+`,
+		},
+		{
+			name:  "escaped",
+			input: []kdtree.Point{NewPoint([]float64{1}, `"quoted"`)},
+			output: `digraph kdtree {
+	node [shape=box];
+	n0 [label="{[1] \"quoted\"}\naxis 0"];
+}
+`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var b bytes.Buffer
+			assert.NoError(t, kdtree.New(test.input).WriteDOT(&b))
+			assert.Equal(t, test.output, b.String())
+		})
+	}
+}
+
+func TestKDTree_WriteSVG(t *testing.T) {
+	var b bytes.Buffer
+	assert.NoError(t, kdtree.New(nil).WriteSVG(&b, 100, 50))
+	assert.Equal(t, `<svg xmlns="http://www.w3.org/2000/svg" width="100" height="50" viewBox="0 0 100 50">
+<rect width="100" height="50" fill="white"/>
+</svg>
+`, b.String())
+
+	b.Reset()
+	tree := kdtree.New([]kdtree.Point{&Point2D{X: 0, Y: 0}, &Point2D{X: 10, Y: 10}, &Point2D{X: 5, Y: 2}, &Point2D{X: 2, Y: 8}})
+	assert.NoError(t, tree.WriteSVG(&b, 120, 120))
+	assert.Equal(t, `<svg xmlns="http://www.w3.org/2000/svg" width="120" height="120" viewBox="0 0 120 120">
+<rect width="120" height="120" fill="white"/>
+<rect x="10.00" y="10.00" width="100.00" height="100.00" fill="none" stroke="gray"/>
+<line x1="60.00" y1="110.00" x2="60.00" y2="10.00" stroke="red"/>
+<line x1="10.00" y1="30.00" x2="60.00" y2="30.00" stroke="blue"/>
+<circle cx="10.00" cy="110.00" r="2" fill="black"/>
+<circle cx="30.00" cy="30.00" r="2" fill="black"/>
+<circle cx="60.00" cy="90.00" r="2" fill="black"/>
+<circle cx="110.00" cy="10.00" r="2" fill="black"/>
+</svg>
+`, b.String())
+}
+
+func TestKDTree_WriteSVGWithGenerator(t *testing.T) {
+	tree := kdtree.New(generateTestCaseData(100))
+	var b bytes.Buffer
+	assert.NoError(t, tree.WriteSVG(&b, 800, 600))
+	assert.Equal(t, 100, strings.Count(b.String(), "<circle"))
+	stats := tree.Stats()
+	assert.Equal(t, stats.Splits[0]+stats.Splits[1], strings.Count(b.String(), "<line"))
+}
+
+func TestKDTree_WriteErrors(t *testing.T) {
+	tree := kdtree.New([]kdtree.Point{&Point3D{X: 1, Y: 2, Z: 3}})
+	assert.EqualError(t, tree.WriteSVG(&bytes.Buffer{}, 100, 100), "kdtree: SVG requires 2 dimensions, got 3")
+
+	w := &failingWriter{}
+	assert.EqualError(t, tree.WriteDOT(w), "write failed")
+	assert.Equal(t, 1, w.writes)
+	w = &failingWriter{}
+	assert.EqualError(t, kdtree.New(nil).WriteSVG(w, 100, 100), "write failed")
+	assert.Equal(t, 1, w.writes)
+}
+
+// failingWriter fails on every write.
+type failingWriter struct {
+	writes int
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	w.writes++
+	return 0, errors.New("write failed")
+}
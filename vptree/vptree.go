@@ -0,0 +1,265 @@
+/*
+ * Copyright 2020 Dennis Kuhnert
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+// Package vptree implements a vantage-point tree for points of arbitrary metric spaces.
+//
+// In contrast to the k-d tree, the points do not need coordinates. Only the distance between two points is
+// required, e.g. the edit distance of strings or the Jaccard distance of sets.
+//
+// The queries accept the kdtree.QueryOption to collect kdtree.QueryStats.
+package vptree
+
+import (
+	"github.com/kyroy/kdtree"
+	"github.com/kyroy/priority-queue"
+	"math"
+	"sort"
+)
+
+// Point specifies one element of the vp-tree.
+type Point interface {
+	// Distance returns the distance to the point p.
+	// It must be a metric: non-negative, symmetric, 0 for equal points and satisfy the triangle inequality.
+	Distance(p Point) float64
+}
+
+// Euclidean wraps a kdtree.Point to use it in the vp-tree with the euclidean distance.
+type Euclidean struct {
+	kdtree.Point
+}
+
+// Distance returns the euclidean distance to p, which must be Euclidean as well.
+func (e Euclidean) Distance(p Point) float64 {
+	o := p.(Euclidean)
+	sum := 0.
+	for i := 0; i < e.Dimensions(); i++ {
+		d := e.Dimension(i) - o.Dimension(i)
+		sum += d * d
+	}
+	return math.Sqrt(sum)
+}
+
+// VPTree represents the vp-tree.
+type VPTree struct {
+	root *node
+}
+
+// New returns a balanced vp-tree.
+func New(points []Point) *VPTree {
+	return &VPTree{
+		root: newVPTree(append([]Point(nil), points...)),
+	}
+}
+
+// newVPTree builds the subtree with the first point as vantage point.
+// The other points are split at the median of their distance to it.
+func newVPTree(points []Point) *node {
+	if len(points) == 0 {
+		return nil
+	}
+
+	n := &node{Point: points[0], size: len(points)}
+	rest := &byDistance{points: points[1:], distances: make([]float64, len(points)-1)}
+	for i, p := range rest.points {
+		rest.distances[i] = n.Distance(p)
+	}
+	sort.Sort(rest)
+
+	mid := len(rest.points) / 2
+	if mid < len(rest.points) {
+		n.radius = rest.distances[mid]
+	}
+	n.inside = newVPTree(rest.points[:mid])
+	n.outside = newVPTree(rest.points[mid:])
+	return n
+}
+
+// Len returns the number of points in the tree.
+func (t *VPTree) Len() int {
+	if t.root == nil {
+		return 0
+	}
+	return t.root.size
+}
+
+// Points returns all points in the tree.
+func (t *VPTree) Points() []Point {
+	points := []Point{}
+	t.root.visit(func(p Point) {
+		points = append(points, p)
+	})
+	return points
+}
+
+// KNN returns the k-nearest neighbours of the given point.
+// The points are sorted by the distance to the given points. Starting with the nearest.
+//
+// Statistics about the search are collected with kdtree.WithQueryStats.
+func (t *VPTree) KNN(p Point, k int, options ...kdtree.QueryOption) []Point {
+	if t.root == nil || p == nil || k <= 0 {
+		return []Point{}
+	}
+
+	s := &knnSearch{p: p, k: k, nearest: pq.NewPriorityQueue(pq.WithMinPrioSize(k))}
+	s.search(t.root, 1)
+	if stats := kdtree.RequestedQueryStats(options...); stats != nil {
+		stats.Add(s.stats)
+	}
+
+	points := make([]Point, 0, k)
+	for s.nearest.Len() > 0 {
+		points = append(points, s.nearest.PopLowest().(Point))
+	}
+	return points
+}
+
+// RadiusSearch returns all points whose distance to the given point p is at most radius.
+// The points are sorted by the distance to the given points. Starting with the nearest.
+//
+// Statistics about the search are collected with kdtree.WithQueryStats.
+func (t *VPTree) RadiusSearch(p Point, radius float64, options ...kdtree.QueryOption) []Point {
+	if t.root == nil || p == nil || radius < 0 {
+		return []Point{}
+	}
+
+	s := &radiusSearch{p: p, radius: radius, result: &byDistance{points: []Point{}}}
+	s.search(t.root, 1)
+	if stats := kdtree.RequestedQueryStats(options...); stats != nil {
+		stats.Add(s.stats)
+	}
+
+	sort.Stable(s.result)
+	return s.result.points
+}
+
+type node struct {
+	Point
+	// radius is the median distance of the other points in the subtree to the vantage point.
+	// All points inside have a distance of at most radius, all points outside a distance of at least radius.
+	radius  float64
+	size    int
+	inside  *node
+	outside *node
+}
+
+func (n *node) visit(fn func(Point)) {
+	if n == nil {
+		return
+	}
+	fn(n.Point)
+	n.inside.visit(fn)
+	n.outside.visit(fn)
+}
+
+// knnSearch holds the state of a k-nearest neighbour search.
+type knnSearch struct {
+	p       Point
+	k       int
+	nearest *pq.PriorityQueue
+	stats   kdtree.QueryStats
+}
+
+// kth returns the distance of the k-th nearest neighbour found so far.
+func (s *knnSearch) kth() float64 {
+	if s.nearest.Len() < s.k {
+		return math.Inf(1)
+	}
+	_, d := s.nearest.Get(s.k - 1)
+	return d
+}
+
+// search adds the nearest neighbours in the subtree n at the given depth to the queue.
+// By the triangle inequality, the points inside are at least d-radius away from p and
+// the points outside at least radius-d, where d is the distance of p to the vantage point.
+func (s *knnSearch) search(n *node, depth int) {
+	if n == nil {
+		return
+	}
+
+	s.stats.NodesVisited++
+	s.stats.DistanceComputations++
+	if depth > s.stats.MaxDepth {
+		s.stats.MaxDepth = depth
+	}
+	d := n.Distance(s.p)
+	if d < s.kth() {
+		s.stats.QueueInserts++
+		s.nearest.Insert(n.Point, d)
+	}
+
+	// search the side of p first
+	if d < n.radius {
+		s.search(n.inside, depth+1)
+		if n.radius-d < s.kth() {
+			s.search(n.outside, depth+1)
+		}
+	} else {
+		s.search(n.outside, depth+1)
+		if d-n.radius < s.kth() {
+			s.search(n.inside, depth+1)
+		}
+	}
+}
+
+// radiusSearch holds the state of a radius search.
+type radiusSearch struct {
+	p      Point
+	radius float64
+	result *byDistance
+	stats  kdtree.QueryStats
+}
+
+func (s *radiusSearch) search(n *node, depth int) {
+	if n == nil {
+		return
+	}
+
+	s.stats.NodesVisited++
+	s.stats.DistanceComputations++
+	if depth > s.stats.MaxDepth {
+		s.stats.MaxDepth = depth
+	}
+	d := n.Distance(s.p)
+	if d <= s.radius {
+		s.result.points = append(s.result.points, n.Point)
+		s.result.distances = append(s.result.distances, d)
+	}
+	if d-n.radius <= s.radius {
+		s.search(n.inside, depth+1)
+	}
+	if n.radius-d <= s.radius {
+		s.search(n.outside, depth+1)
+	}
+}
+
+// byDistance sorts points by their distances.
+type byDistance struct {
+	points    []Point
+	distances []float64
+}
+
+func (b *byDistance) Len() int {
+	return len(b.points)
+}
+
+func (b *byDistance) Less(i, j int) bool {
+	return b.distances[i] < b.distances[j]
+}
+
+func (b *byDistance) Swap(i, j int) {
+	b.points[i], b.points[j] = b.points[j], b.points[i]
+	b.distances[i], b.distances[j] = b.distances[j], b.distances[i]
+}
@@ -0,0 +1,179 @@
+/*
+ * Copyright 2020 Dennis Kuhnert
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package vptree_test
+
+import (
+	"github.com/kyroy/kdtree"
+	. "github.com/kyroy/kdtree/points"
+	"github.com/kyroy/kdtree/vptree"
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+// word is a string with the edit distance as metric.
+type word string
+
+func (w word) Distance(p vptree.Point) float64 {
+	a, b := []rune(string(w)), []rune(string(p.(word)))
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = minimum(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return float64(previous[len(b)])
+}
+
+func minimum(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
+
+func TestNew(t *testing.T) {
+	assert.Equal(t, 0, vptree.New(nil).Len())
+	assert.Equal(t, []vptree.Point{}, vptree.New(nil).Points())
+
+	input := []vptree.Point{word("kitten"), word("sitting"), word("mitten"), word("fitting"), word("kitchen")}
+	tree := vptree.New(input)
+	assert.Equal(t, 5, tree.Len())
+	assert.ElementsMatch(t, input, tree.Points())
+}
+
+func TestVPTree_KNN(t *testing.T) {
+	tree := vptree.New([]vptree.Point{word("kitten"), word("sitting"), word("mitten"), word("fitting"), word("kitchen"), word("bitten")})
+
+	assert.Equal(t, []vptree.Point{}, vptree.New(nil).KNN(word("kitten"), 1))
+	assert.Equal(t, []vptree.Point{}, tree.KNN(nil, 1))
+	assert.Equal(t, []vptree.Point{}, tree.KNN(word("kitten"), 0))
+	assert.Equal(t, []vptree.Point{word("kitten")}, tree.KNN(word("kitten"), 1))
+	assert.Equal(t, []vptree.Point{word("sitting"), word("fitting")}, tree.KNN(word("sitting"), 2))
+	assert.Len(t, tree.KNN(word("kitten"), 10), 6)
+}
+
+func TestVPTree_RadiusSearch(t *testing.T) {
+	tree := vptree.New([]vptree.Point{word("kitten"), word("sitting"), word("mitten"), word("fitting"), word("kitchen"), word("bitten")})
+
+	assert.Equal(t, []vptree.Point{}, tree.RadiusSearch(word("kitten"), -1))
+	assert.Equal(t, []vptree.Point{}, tree.RadiusSearch(nil, 1))
+	assert.Equal(t, []vptree.Point{word("kitten")}, tree.RadiusSearch(word("kitten"), 0))
+
+	result := tree.RadiusSearch(word("kitten"), 1)
+	assert.Equal(t, word("kitten"), result[0])
+	assert.ElementsMatch(t, []vptree.Point{word("kitten"), word("mitten"), word("bitten")}, result)
+	assert.ElementsMatch(t, []vptree.Point{word("kitten"), word("mitten"), word("bitten"), word("kitchen")}, tree.RadiusSearch(word("kitten"), 2))
+}
+
+func TestVPTreeWithWords(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	input := make([]vptree.Point, 2000)
+	for i := range input {
+		input[i] = randomWord(r)
+	}
+	tree := vptree.New(input)
+
+	for i := 0; i < 50; i++ {
+		q := randomWord(r)
+		expected := bruteForce(input, q)
+
+		knn := tree.KNN(q, 10)
+		assert.Len(t, knn, 10)
+		for j, p := range knn {
+			assert.Equal(t, q.Distance(expected[j]), q.Distance(p))
+		}
+
+		var within []vptree.Point
+		for _, p := range expected {
+			if q.Distance(p) <= 2 {
+				within = append(within, p)
+			}
+		}
+		assert.ElementsMatch(t, within, tree.RadiusSearch(q, 2))
+	}
+}
+
+func TestVPTreeWithEuclidean(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	input := make([]kdtree.Point, 10000)
+	wrapped := make([]vptree.Point, len(input))
+	for i := range input {
+		input[i] = &Point2D{X: r.Float64()*3000 - 1500, Y: r.Float64()*3000 - 1500}
+		wrapped[i] = vptree.Euclidean{Point: input[i]}
+	}
+	kd := kdtree.New(append([]kdtree.Point(nil), input...))
+	vp := vptree.New(wrapped)
+
+	var stats kdtree.QueryStats
+	for i := 0; i < 100; i++ {
+		q := &Point2D{X: r.Float64()*3000 - 1500, Y: r.Float64()*3000 - 1500}
+		expected := kd.KNN(q, 5)
+		actual := vp.KNN(vptree.Euclidean{Point: q}, 5, kdtree.WithQueryStats(&stats))
+		for j := range expected {
+			assert.Equal(t, expected[j], actual[j].(vptree.Euclidean).Point)
+		}
+		assert.Equal(t, kd.RadiusCount(q, 100), len(vp.RadiusSearch(vptree.Euclidean{Point: q}, 100, kdtree.WithQueryStats(&stats))))
+	}
+	assert.Equal(t, stats.NodesVisited, stats.DistanceComputations)
+	assert.Less(t, stats.NodesVisited, 200*len(input)/10)
+	assert.LessOrEqual(t, stats.MaxDepth, 14)
+}
+
+func BenchmarkVPTree_KNN(b *testing.B) {
+	r := rand.New(rand.NewSource(1))
+	input := make([]vptree.Point, 100000)
+	for i := range input {
+		input[i] = vptree.Euclidean{Point: &Point2D{X: r.Float64()*3000 - 1500, Y: r.Float64()*3000 - 1500}}
+	}
+	tree := vptree.New(input)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tree.KNN(input[i%len(input)], 5)
+	}
+}
+
+func randomWord(r *rand.Rand) word {
+	letters := make([]rune, 3+r.Intn(5))
+	for i := range letters {
+		letters[i] = rune('a' + r.Intn(4))
+	}
+	return word(letters)
+}
+
+// bruteForce returns all points sorted by their distance to q.
+func bruteForce(input []vptree.Point, q vptree.Point) []vptree.Point {
+	sorted := append([]vptree.Point(nil), input...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return q.Distance(sorted[i]) < q.Distance(sorted[j])
+	})
+	return sorted
+}